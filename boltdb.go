@@ -1,6 +1,7 @@
 package boltdb
 
 import (
+	"sync"
 	"time"
 
 	"github.com/RangelReale/osin"
@@ -17,16 +18,54 @@ var (
 	accessBucket    = []byte("access")
 	refreshBucket   = []byte("refresh")
 
+	familyBucket         = []byte("refresh_family")
+	retiredRefreshBucket = []byte("refresh_retired")
+
+	metaBucket = []byte("meta")
+
+	registrationBucket = []byte("registration")
+
+	keysBucket = []byte("keys")
+
+	authorizeExpiryBucket = []byte("authorize_expiry")
+	accessExpiryBucket    = []byte("access_expiry")
+	clientIndexBucket     = []byte("client_index")
+	subjectIndexBucket    = []byte("subject_index")
+	familyIndexBucket     = []byte("family_index")
+	lineageIndexBucket    = []byte("lineage_index")
+
 	allBuckets = [][]byte{
 		clientBucket,
 		authorizeBucket,
 		accessBucket,
 		refreshBucket,
+		familyBucket,
+		retiredRefreshBucket,
+		metaBucket,
+		registrationBucket,
+		keysBucket,
 	}
 )
 
 type Storage struct {
 	db *bolt.DB
+
+	now              func() time.Time
+	enforceExpiry    bool
+	clockSkew        time.Duration
+	refreshLifetime  time.Duration
+	chainDepth       int
+	codec            model.UserDataCodec
+	strictUserData   bool
+	userDataHook     func(error)
+	clientCodec      ClientCodec
+	pepper           []byte
+	secretHasher     SecretHasher
+	keys             KeyProvider
+	subjectExtractor SubjectExtractor
+
+	mu      sync.Mutex
+	sweeper *sweeper
 }
 
 func (s *Storage) get(tx *bolt.Tx, bucket []byte, key []byte, dest interface{}) (err error) {
@@ -34,6 +73,12 @@ func (s *Storage) get(tx *bolt.Tx, bucket []byte, key []byte, dest interface{})
 	if value == nil {
 		err = osin.ErrNotFound
 	} else {
+		if s.keys != nil {
+			value, err = s.open(bucket, key, value)
+			if err != nil {
+				return err
+			}
+		}
 		switch dest := dest.(type) {
 		case proto.Message:
 			err = proto.Unmarshal(value, dest)
@@ -74,6 +119,13 @@ func (s *Storage) put(tx *bolt.Tx, bucket []byte, key []byte, value interface{})
 			data, _ = proto.Marshal(value)
 		}
 	}
+	if s.keys != nil {
+		var err error
+		data, err = s.seal(bucket, key, data)
+		if err != nil {
+			return err
+		}
+	}
 	return tx.Bucket(bucket).Put(key, data)
 }
 
@@ -82,13 +134,20 @@ func (s *Storage) delete(tx *bolt.Tx, bucket []byte, key []byte) error {
 }
 
 func (s *Storage) deleteClient(tx *bolt.Tx, id string) error {
+	msg := &model.Client{}
+	if s.get(tx, clientBucket, []byte(id), msg) == nil && msg.RegistrationToken != "" {
+		err := s.delete(tx, registrationBucket, []byte(msg.RegistrationToken))
+		if err != nil {
+			return err
+		}
+	}
 	return s.delete(tx, clientBucket, []byte(id))
 }
 
 func (s *Storage) putClient(tx *bolt.Tx, client osin.Client, f writeFunc) error {
-	userdata, err := model.DefaultUserDataCodec.EncodeUserData(client.GetUserData())
+	userdata, err := s.encodeUserData("client", client.GetUserData())
 	if err != nil {
-		//TODO: log?
+		return err
 	}
 	msg := model.Client{
 		Id:          client.GetId(),
@@ -96,6 +155,41 @@ func (s *Storage) putClient(tx *bolt.Tx, client osin.Client, f writeFunc) error
 		RedirectUri: client.GetRedirectUri(),
 		UserData:    userdata,
 	}
+	if metadata := MetadataOf(client); metadata != nil {
+		err = metadata.Validate()
+		if err != nil {
+			return err
+		}
+		msg.ClientMetadata = metadata.model()
+	}
+	// The refresh policy, the registration token and the rotated secrets are
+	// only set by SetRefreshPolicy, SetRegistrationToken, RotateClientSecret
+	// and RevokeClientSecret.
+	old := &model.Client{}
+	if s.get(tx, clientBucket, []byte(msg.Id), old) == nil {
+		msg.RefreshPolicy = old.RefreshPolicy
+		msg.RegistrationToken = old.RegistrationToken
+		msg.Secrets = old.Secrets
+		msg.CreatedAt = old.CreatedAt
+	} else {
+		msg.CreatedAt, _ = s.now().MarshalBinary()
+	}
+	// A HashedClient does not know its secret, its hash is kept as is.
+	if c, ok := unwrapClient(client).(*HashedClient); ok {
+		msg.Secret, msg.SecretHash = c.secret, c.secretHash
+	}
+	if s.clientCodec != nil {
+		err = s.clientCodec.EncodeClient(client, &msg)
+		if err != nil {
+			return err
+		}
+	}
+	if s.secretHasher != nil {
+		err = s.hashClientSecret(&msg)
+		if err != nil {
+			return err
+		}
+	}
 	return f(tx, clientBucket, []byte(msg.Id), &msg)
 }
 
@@ -105,25 +199,58 @@ func (s *Storage) getClient(tx *bolt.Tx, id string) (osin.Client, error) {
 	if err != nil {
 		return nil, err
 	}
-	userdata, _ := model.DefaultUserDataCodec.DecodeUserData(msg.UserData)
-	return &osin.DefaultClient{
-		Id:          msg.Id,
-		Secret:      msg.Secret,
-		RedirectUri: msg.RedirectUri,
-		UserData:    userdata,
-	}, nil
+	userdata, err := s.decodeUserData("client", msg.UserData)
+	if err != nil {
+		return nil, err
+	}
+	var client osin.Client
+	if s.secretHasher != nil || msg.SecretHash != nil || len(msg.Secrets) > 0 {
+		client = &HashedClient{
+			Id:          msg.Id,
+			RedirectUri: msg.RedirectUri,
+			UserData:    userdata,
+			storage:     s,
+			secret:      msg.Secret,
+			secretHash:  msg.SecretHash,
+			secrets:     msg.Secrets,
+		}
+	} else {
+		client = &osin.DefaultClient{
+			Id:          msg.Id,
+			Secret:      msg.Secret,
+			RedirectUri: msg.RedirectUri,
+			UserData:    userdata,
+		}
+	}
+	if msg.ClientMetadata != nil {
+		client = &RegisteredClient{Client: client, Metadata: clientMetadata(msg.ClientMetadata)}
+	}
+	if s.clientCodec != nil {
+		return s.clientCodec.DecodeClient(msg, client)
+	}
+	return client, nil
 }
 
 func (s *Storage) deleteAuthorize(tx *bolt.Tx, code string) error {
+	msg := &model.AuthorizeData{}
+	if s.get(tx, authorizeBucket, []byte(code), msg) == nil {
+		err := s.unindexAuthorize(tx, msg)
+		if err != nil {
+			return err
+		}
+	}
 	return s.delete(tx, authorizeBucket, []byte(code))
 }
 
 func (s *Storage) putAuthorize(tx *bolt.Tx, authorize *osin.AuthorizeData, f writeFunc) error {
 	createdAt, _ := authorize.CreatedAt.MarshalBinary()
-	userdata, _ := model.DefaultUserDataCodec.EncodeUserData(authorize.UserData)
+	userdata, err := s.encodeUserData("authorize", authorize.UserData)
+	if err != nil {
+		return err
+	}
 	msg := model.AuthorizeData{
 		ClientId:            authorize.Client.GetId(),
-		Code:                authorize.Code,
+		Code:                s.tokenKey(authorize.Code),
 		ExpiresIn:           authorize.ExpiresIn,
 		Scope:               authorize.Scope,
 		RedirectUri:         authorize.RedirectUri,
@@ -133,7 +260,11 @@ func (s *Storage) putAuthorize(tx *bolt.Tx, authorize *osin.AuthorizeData, f wri
 		CodeChallenge:       authorize.CodeChallenge,
 		CodeChallengeMethod: authorize.CodeChallengeMethod,
 	}
-	return f(tx, authorizeBucket, []byte(msg.Code), &msg)
+	err = f(tx, authorizeBucket, []byte(msg.Code), &msg)
+	if err != nil {
+		return err
+	}
+	return s.indexAuthorize(tx, &msg)
 }
 
 func (s *Storage) getAuthorize(tx *bolt.Tx, code string) (*osin.AuthorizeData, error) {
@@ -142,13 +273,19 @@ func (s *Storage) getAuthorize(tx *bolt.Tx, code string) (*osin.AuthorizeData, e
 	if err != nil {
 		return nil, err
 	}
+	return s.authorizeData(tx, msg)
+}
 
+func (s *Storage) authorizeData(tx *bolt.Tx, msg *model.AuthorizeData) (*osin.AuthorizeData, error) {
 	client, err := s.getClient(tx, msg.ClientId)
 	if err != nil {
 		return nil, err
 	}
 
-	userdata, _ := model.DefaultUserDataCodec.DecodeUserData(msg.UserData)
+	userdata, err := s.decodeUserData("authorize", msg.UserData)
+	if err != nil {
+		return nil, err
+	}
 	createdAt := time.Time{}
 	createdAt.UnmarshalBinary(msg.CreatedAt)
 
@@ -167,16 +304,30 @@ func (s *Storage) getAuthorize(tx *bolt.Tx, code string) (*osin.AuthorizeData, e
 }
 
 func (s *Storage) deleteAccess(tx *bolt.Tx, token string) error {
+	msg := &model.AccessData{}
+	if s.get(tx, accessBucket, []byte(token), msg) == nil {
+		err := s.unindexAccess(tx, msg)
+		if err != nil {
+			return err
+		}
+		err = s.unlinkAccess(tx, msg.PrevAccessToken, token)
+		if err != nil {
+			return err
+		}
+	}
 	return s.delete(tx, accessBucket, []byte(token))
 }
 
-func (s *Storage) putAccess(tx *bolt.Tx, access *osin.AccessData, f writeFunc) error {
+func (s *Storage) putAccess(tx *bolt.Tx, access *osin.AccessData, f writeFunc) (*model.AccessData, error) {
 	createdAt, _ := access.CreatedAt.MarshalBinary()
-	userdata, _ := model.DefaultUserDataCodec.EncodeUserData(access.UserData)
+	userdata, err := s.encodeUserData("access", access.UserData)
+	if err != nil {
+		return nil, err
+	}
 	msg := model.AccessData{
 		ClientId:     access.Client.GetId(),
-		AccessToken:  access.AccessToken,
-		RefreshToken: access.RefreshToken,
+		AccessToken:  s.tokenKey(access.AccessToken),
+		RefreshToken: s.tokenKey(access.RefreshToken),
 		ExpiresIn:    access.ExpiresIn,
 		Scope:        access.Scope,
 		RedirectUri:  access.RedirectUri,
@@ -184,15 +335,50 @@ func (s *Storage) putAccess(tx *bolt.Tx, access *osin.AccessData, f writeFunc) e
 		UserData:     userdata,
 	}
 
+	// The authorize data the chain started from is kept along, as osin
+	// removes the code once exchanged.
 	if access.AuthorizeData != nil {
-		msg.AuthorizeCode = access.AuthorizeData.Code
+		msg.AuthorizeCode = s.tokenKey(access.AuthorizeData.Code)
+		msg.Origin = originData(access.AuthorizeData, msg.AuthorizeCode)
 	}
 
+	// The previous access data comes from LoadRefresh, which only knows the
+	// hashed key of its access token. Its refresh token is the one consumed.
+	consumed := ""
 	if access.AccessData != nil {
-		msg.PrevAccessToken = access.AccessData.AccessToken
+		consumed = s.removeKey(access.AccessData.RefreshToken)
+		msg.PrevAccessToken = s.removeKey(access.AccessData.AccessToken)
+		if msg.Origin == nil {
+			msg.Origin = s.prevOrigin(tx, msg.PrevAccessToken)
+		}
+	}
+
+	err = s.joinFamily(tx, &msg, consumed)
+	if err != nil {
+		return nil, err
+	}
+	err = f(tx, accessBucket, []byte(msg.AccessToken), &msg)
+	if err != nil {
+		return nil, err
 	}
+	return &msg, s.indexAccess(tx, &msg)
+}
 
-	return f(tx, accessBucket, []byte(msg.AccessToken), &msg)
+// originData returns the authorize data access data keeps, without its
+// UserData, which the access data has.
+func originData(authorize *osin.AuthorizeData, code string) *model.AuthorizeData {
+	createdAt, _ := authorize.CreatedAt.MarshalBinary()
+	return &model.AuthorizeData{
+		ClientId:            authorize.Client.GetId(),
+		Code:                code,
+		ExpiresIn:           authorize.ExpiresIn,
+		Scope:               authorize.Scope,
+		RedirectUri:         authorize.RedirectUri,
+		State:               authorize.State,
+		CreatedAt:           createdAt,
+		CodeChallenge:       authorize.CodeChallenge,
+		CodeChallengeMethod: authorize.CodeChallengeMethod,
+	}
 }
 
 func (s *Storage) getAccess(tx *bolt.Tx, token string) (*osin.AccessData, error) {
@@ -201,17 +387,51 @@ func (s *Storage) getAccess(tx *bolt.Tx, token string) (*osin.AccessData, error)
 	if err != nil {
 		return nil, err
 	}
+	return s.accessData(tx, msg)
+}
 
-	client, err := s.getClient(tx, msg.ClientId)
-	if err != nil {
-		return nil, err
+func (s *Storage) accessData(tx *bolt.Tx, msg *model.AccessData) (*osin.AccessData, error) {
+	return s.accessChain(tx, msg, s.chainDepth, nil)
+}
+
+// accessChain converts msg, loading depth levels of previous access data.
+// client is reused by the levels of the same client, to look it up once.
+func (s *Storage) accessChain(tx *bolt.Tx, msg *model.AccessData, depth int, client osin.Client) (*osin.AccessData, error) {
+	var err error
+	if client == nil || client.GetId() != msg.ClientId {
+		client, err = s.getClient(tx, msg.ClientId)
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	authorize, _ := s.getAuthorize(tx, msg.AuthorizeCode)
-	access, _ := s.getAccess(tx, msg.PrevAccessToken)
+	// The authorize and previous access data are loaded if available, but
+	// their UserData must decode in strict mode.
+	var (
+		authorize *osin.AuthorizeData
+		access    *osin.AccessData
+	)
+	if msg.AuthorizeCode != "" {
+		authorize, err = s.getAuthorize(tx, msg.AuthorizeCode)
+		if _, ok := err.(*UserDataError); ok {
+			return nil, err
+		}
+	}
+	if depth > 0 && msg.PrevAccessToken != "" {
+		prev := &model.AccessData{}
+		if s.get(tx, accessBucket, []byte(msg.PrevAccessToken), prev) == nil {
+			access, err = s.accessChain(tx, prev, depth-1, client)
+			if _, ok := err.(*UserDataError); ok {
+				return nil, err
+			}
+		}
+	}
 	createdAt := time.Time{}
 	createdAt.UnmarshalBinary(msg.CreatedAt)
-	userdata, _ := model.DefaultUserDataCodec.DecodeUserData(msg.UserData)
+	userdata, err := s.decodeUserData("access", msg.UserData)
+	if err != nil {
+		return nil, err
+	}
 
 	return &osin.AccessData{
 		Client:        client,
@@ -227,35 +447,63 @@ func (s *Storage) getAccess(tx *bolt.Tx, token string) (*osin.AccessData, error)
 	}, nil
 }
 
-func (s *Storage) putRefresh(tx *bolt.Tx, access *osin.AccessData, f writeFunc) error {
+func (s *Storage) putRefresh(tx *bolt.Tx, access *model.AccessData, f writeFunc) error {
 	if access.RefreshToken == "" {
 		return nil
 	}
-	return f(tx, refreshBucket, []byte(access.RefreshToken), []byte(access.AccessToken))
+	err := f(tx, refreshBucket, []byte(access.RefreshToken), []byte(access.AccessToken))
+	if err != nil {
+		return err
+	}
+	return s.indexRefresh(tx, access, access.RefreshToken)
 }
 
 func (s *Storage) deleteRefresh(tx *bolt.Tx, token string) error {
-	return s.delete(tx, refreshBucket, []byte(token))
-}
-
-func (s *Storage) getRefresh(tx *bolt.Tx, token string) (*osin.AccessData, error) {
 	var accessToken []byte
-	err := s.get(tx, refreshBucket, []byte(token), &accessToken)
-	if err != nil {
-		return nil, err
+	if s.get(tx, refreshBucket, []byte(token), &accessToken) == nil {
+		// The index entry is left behind if the access data is gone, to be
+		// removed along with the client.
+		msg := &model.AccessData{}
+		if s.get(tx, accessBucket, accessToken, msg) == nil {
+			err := s.unindexRefresh(tx, msg, token)
+			if err != nil {
+				return err
+			}
+			// Removing the current refresh token ends its family.
+			if msg.FamilyId != "" && s.currentRefresh(tx, msg.FamilyId) == token {
+				err = s.endFamily(tx, msg.FamilyId)
+				if err != nil {
+					return err
+				}
+			}
+		}
 	}
-	return s.getAccess(tx, string(accessToken))
+	return s.delete(tx, refreshBucket, []byte(token))
 }
 
+// Clone returns a view of the Storage sharing its resources. Closing the
+// clone does not close the Storage.
 func (s *Storage) Clone() osin.Storage {
-	return s
+	return clone{s}
 }
 
 // Close the resources the Storage potentially holds (using Clone for example)
 func (s *Storage) Close() {
+	s.StopSweeper()
+}
 
+// clone is returned by Storage.Clone. osin closes its clones after every
+// request, so closing one must not stop the parent's background work.
+type clone struct {
+	*Storage
 }
 
+func (c clone) Clone() osin.Storage {
+	return c
+}
+
+func (c clone) Close() {}
+
 func (s *Storage) CreateClient(client osin.Client) error {
 	return s.db.Update(func(tx *bolt.Tx) error {
 		return s.putClient(tx, client, s.insert)
@@ -268,8 +516,14 @@ func (s *Storage) UpdateClient(client osin.Client) error {
 	})
 }
 
+// RemoveClient deletes the client along with all its authorize codes, access
+// tokens and refresh tokens.
 func (s *Storage) RemoveClient(id string) error {
 	return s.db.Update(func(tx *bolt.Tx) error {
+		_, err := s.deleteIndexed(tx, clientIndexBucket, id)
+		if err != nil {
+			return err
+		}
 		return s.deleteClient(tx, id)
 	})
 }
@@ -281,6 +535,24 @@ func (s *Storage) GetClient(id string) (osin.Client, error) {
 	return s.getClient(tx, id)
 }
 
+// ClientCreatedAt returns when the client was created, or the zero time for
+// clients created before it was recorded.
+func (s *Storage) ClientCreatedAt(id string) (time.Time, error) {
+	var createdAt time.Time
+	err := s.db.View(func(tx *bolt.Tx) error {
+		msg := &model.Client{}
+		err := s.get(tx, clientBucket, []byte(id), msg)
+		if err != nil {
+			return err
+		}
+		if len(msg.CreatedAt) > 0 {
+			createdAt.UnmarshalBinary(msg.CreatedAt)
+		}
+		return nil
+	})
+	return createdAt, err
+}
+
 // SaveAuthorize saves authorize data.
 func (s *Storage) SaveAuthorize(authorize *osin.AuthorizeData) error {
 	return s.db.Update(func(tx *bolt.Tx) error {
@@ -291,29 +563,62 @@ func (s *Storage) SaveAuthorize(authorize *osin.AuthorizeData) error {
 // LoadAuthorize looks up AuthorizeData by a code.
 // Client information MUST be loaded together.
 // Optionally can return error if expired.
+// A code consumed by ExchangeAuthorizeCode is reported as reused, after
+// revoking the tokens issued from it.
 func (s *Storage) LoadAuthorize(code string) (*osin.AuthorizeData, error) {
-	tx, _ := s.db.Begin(false)
-	defer tx.Rollback()
-	return s.getAuthorize(tx, code)
+	var (
+		authorize *osin.AuthorizeData
+		used      bool
+	)
+	err := s.db.View(func(tx *bolt.Tx) error {
+		msg := &model.AuthorizeData{}
+		err := s.get(tx, authorizeBucket, []byte(s.tokenKey(code)), msg)
+		if err != nil {
+			return err
+		}
+		if msg.Used {
+			used = true
+			return nil
+		}
+		authorize, err = s.authorizeData(tx, msg)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if used {
+		return nil, s.codeReused(s.tokenKey(code))
+	}
+	if s.expired(authorize.ExpireAt()) {
+		return nil, storage.ErrExpired
+	}
+	authorize.Code = code
+	return authorize, nil
 }
 
 // RemoveAuthorize revokes or deletes the authorization code.
 func (s *Storage) RemoveAuthorize(code string) error {
 	return s.db.Update(func(tx *bolt.Tx) error {
-		return s.deleteAuthorize(tx, code)
+		return s.deleteAuthorize(tx, s.removeKey(code))
 	})
 }
 
 // SaveAccess writes AccessData.
 // If RefreshToken is not blank, it must save in a way that can be loaded using LoadRefresh.
+// Access data refreshed with a superseded refresh token revokes its family
+// and returns storage.ErrRefreshTokenReused.
 func (s *Storage) SaveAccess(access *osin.AccessData) error {
-	return s.db.Update(func(tx *bolt.Tx) error {
-		err := s.putAccess(tx, access, s.insert)
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		msg, err := s.putAccess(tx, access, s.insert)
 		if err != nil {
 			return err
 		}
-		return s.putRefresh(tx, access, s.insert)
+		return s.putRefresh(tx, msg, s.insert)
 	})
+	if reused, ok := err.(*familyReusedError); ok {
+		return s.familyReused(reused.family)
+	}
+	return err
 }
 
 // LoadAccess retrieves access data by token. Client information MUST be loaded together.
@@ -322,29 +627,108 @@ func (s *Storage) SaveAccess(access *osin.AccessData) error {
 func (s *Storage) LoadAccess(token string) (*osin.AccessData, error) {
 	tx, _ := s.db.Begin(false)
 	defer tx.Rollback()
-	return s.getAccess(tx, token)
+	access, err := s.getAccess(tx, s.tokenKey(token))
+	if err != nil {
+		return nil, err
+	}
+	if s.expired(access.ExpireAt()) {
+		return nil, storage.ErrExpired
+	}
+	access.AccessToken = token
+	return access, nil
+}
+
+// LoadPrevAccess retrieves the access data access was refreshed from, without
+// its own previous access data, so the chain beyond the depth loaded with
+// access data can be walked on demand. It returns osin.ErrNotFound at the
+// start of the chain. The returned AccessToken is the stored key, as with
+// the AccessData returned by LoadRefresh.
+func (s *Storage) LoadPrevAccess(access *osin.AccessData) (*osin.AccessData, error) {
+	var prev *osin.AccessData
+	err := s.db.View(func(tx *bolt.Tx) error {
+		msg := &model.AccessData{}
+		err := s.get(tx, accessBucket, []byte(s.removeKey(access.AccessToken)), msg)
+		if err != nil {
+			return err
+		}
+		if msg.PrevAccessToken == "" {
+			return osin.ErrNotFound
+		}
+		prevMsg := &model.AccessData{}
+		err = s.get(tx, accessBucket, []byte(msg.PrevAccessToken), prevMsg)
+		if err != nil {
+			return err
+		}
+		prev, err = s.accessChain(tx, prevMsg, 0, nil)
+		return err
+	})
+	return prev, err
 }
 
-// RemoveAccess revokes or deletes an AccessData.
+// RemoveAccess revokes or deletes an AccessData, along with its refresh
+// token, which is of no use without it.
 func (s *Storage) RemoveAccess(token string) error {
 	return s.db.Update(func(tx *bolt.Tx) error {
-		return s.deleteAccess(tx, token)
+		return s.revokeAccess(tx, s.removeKey(token))
 	})
 }
 
 // LoadRefresh retrieves refresh AccessData. Client information MUST be loaded together.
 // AuthorizeData and AccessData DON'T NEED to be loaded if not easily available.
 // Optionally can return error if expired.
+// A refresh token superseded by rotation is reported as reused, after
+// revoking its whole family. Expiry covers the refresh token lifetime, with
+// EnforceExpiry, and the refresh policy of the client, always.
 func (s *Storage) LoadRefresh(token string) (*osin.AccessData, error) {
-	tx, _ := s.db.Begin(false)
-	defer tx.Rollback()
-	return s.getRefresh(tx, token)
+	var (
+		access          *osin.AccessData
+		reused          string
+		expiresAt       time.Time
+		policyExpiresAt time.Time
+	)
+	key := s.tokenKey(token)
+	err := s.db.View(func(tx *bolt.Tx) error {
+		var accessToken []byte
+		err := s.get(tx, refreshBucket, []byte(key), &accessToken)
+		if err == osin.ErrNotFound {
+			if reused = s.retiredFamily(tx, key); reused != "" {
+				return nil
+			}
+		}
+		if err != nil {
+			return err
+		}
+		msg := &model.AccessData{}
+		err = s.get(tx, accessBucket, accessToken, msg)
+		if err != nil {
+			return err
+		}
+		if msg.FamilyId != "" && s.currentRefresh(tx, msg.FamilyId) != key {
+			reused = msg.FamilyId
+			return nil
+		}
+		expiresAt = s.refreshExpiresAt(tx, msg)
+		policyExpiresAt = s.policyExpiresAt(tx, msg)
+		access, err = s.accessData(tx, msg)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if reused != "" {
+		return nil, s.familyReused(reused)
+	}
+	if !expiresAt.IsZero() && s.expired(expiresAt) || !policyExpiresAt.IsZero() && s.past(policyExpiresAt) {
+		return nil, storage.ErrExpired
+	}
+	access.RefreshToken = token
+	return access, nil
 }
 
 // RemoveRefresh revokes or deletes refresh AccessData.
 func (s *Storage) RemoveRefresh(token string) error {
 	return s.db.Update(func(tx *bolt.Tx) error {
-		return s.deleteRefresh(tx, token)
+		return s.deleteRefresh(tx, s.removeKey(token))
 	})
 }
 
@@ -357,12 +741,35 @@ func (s *Storage) InitDB() error {
 				return err
 			}
 		}
-		return nil
+		err := s.initEncryption(tx)
+		if err != nil {
+			return err
+		}
+		err = s.initSubjectKey(tx)
+		if err != nil {
+			return err
+		}
+		err = s.initIndexes(tx)
+		if err != nil {
+			return err
+		}
+		err = s.initAccessExpiry(tx)
+		if err != nil {
+			return err
+		}
+		return s.initTokenHashing(tx)
 	})
 }
 
-func New(db *bolt.DB) *Storage {
-	return &Storage{
-		db: db,
+func New(db *bolt.DB, opts ...Option) *Storage {
+	s := &Storage{
+		db:         db,
+		now:        time.Now,
+		chainDepth: 1,
+		codec:      model.DefaultUserDataCodec,
+	}
+	for _, opt := range opts {
+		opt(s)
 	}
+	return s
 }
@@ -16,6 +16,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/dcalandria/osin-boltdb/model"
 	"github.com/dcalandria/osin-boltdb/storage"
 )
 
@@ -59,6 +60,22 @@ func TestMain(m *testing.M) {
 	os.Exit(retCode)
 }
 
+// newStore opens a Storage backed by a fresh database file, removed when the
+// test ends.
+func newStore(t testing.TB, opts ...Option) *Storage {
+	filename := path.Join(os.TempDir(), randomFilename(10)+".db")
+	db, err := bolt.Open(filename, 0655, bolt.DefaultOptions)
+	require.Nil(t, err)
+	s := New(db, opts...)
+	require.Nil(t, s.InitDB())
+	t.Cleanup(func() {
+		s.Close()
+		db.Close()
+		os.Remove(filename)
+	})
+	return s
+}
+
 func TestClientOperations(t *testing.T) {
 	create := &osin.DefaultClient{Id: "1", Secret: "secret", RedirectUri: "http://localhost/", UserData: ""}
 	createClient(t, store, create)
@@ -245,3 +262,57 @@ func getClient(t *testing.T, store storage.Storage, set osin.Client) {
 func createClient(t *testing.T, store storage.Storage, set osin.Client) {
 	require.Nil(t, store.CreateClient(set))
 }
+
+// reverseCodec stores strings reversed.
+type reverseCodec struct{}
+
+func reverse(b []byte) []byte {
+	r := make([]byte, len(b))
+	for i := range b {
+		r[len(b)-1-i] = b[i]
+	}
+	return r
+}
+
+func (reverseCodec) EncodeUserData(v interface{}) (*model.UserData, error) {
+	userData, err := model.DefaultUserDataCodec.EncodeUserData(v)
+	if err == nil {
+		userData.Data = reverse(userData.Data)
+	}
+	return userData, err
+}
+
+func (reverseCodec) DecodeUserData(userData *model.UserData) (interface{}, error) {
+	return model.DefaultUserDataCodec.DecodeUserData(&model.UserData{Type: userData.Type, Name: userData.Name, Data: reverse(userData.Data)})
+}
+
+func TestUserDataCodec(t *testing.T) {
+	for _, c := range []struct {
+		store  *Storage
+		stored string
+	}{
+		{newStore(t, WithUserDataCodec(reverseCodec{})), "ecila"},
+		{newStore(t), "alice"},
+	} {
+		client := &osin.DefaultClient{Id: "codec", Secret: "secret", RedirectUri: "http://localhost/", UserData: "alice"}
+		createClient(t, c.store, client)
+		getClient(t, c.store, client)
+		authorize := &osin.AuthorizeData{Client: client, Code: uuid.New(), ExpiresIn: 60, CreatedAt: time.Now(), UserData: "alice"}
+		require.Nil(t, c.store.SaveAuthorize(authorize))
+		access := &osin.AccessData{Client: client, AuthorizeData: authorize, AccessToken: uuid.New(), ExpiresIn: 60, CreatedAt: time.Now(), UserData: "alice"}
+		require.Nil(t, c.store.SaveAccess(access))
+
+		loaded, err := c.store.LoadAccess(access.AccessToken)
+		require.Nil(t, err)
+		require.Equal(t, "alice", loaded.UserData)
+		require.Equal(t, "alice", loaded.AuthorizeData.UserData)
+		require.Equal(t, "alice", loaded.Client.GetUserData())
+
+		c.store.db.View(func(tx *bolt.Tx) error {
+			msg := &model.Client{}
+			require.Nil(t, c.store.get(tx, clientBucket, []byte(client.Id), msg))
+			require.Equal(t, c.stored, string(msg.UserData.Data))
+			return nil
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package boltdb
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/RangelReale/osin"
+	"github.com/pborman/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+// saveChain saves n access data, each refreshed from the previous one, and
+// returns the last.
+func saveChain(t testing.TB, s *Storage, client osin.Client, n int) *osin.AccessData {
+	var access *osin.AccessData
+	for i := 0; i < n; i++ {
+		access = &osin.AccessData{Client: client, AccessData: access, AccessToken: uuid.New(), RefreshToken: uuid.New(), ExpiresIn: 60, CreatedAt: time.Now()}
+		require.Nil(t, s.SaveAccess(access))
+	}
+	return access
+}
+
+// chainLength returns the number of levels of access data loaded.
+func chainLength(access *osin.AccessData) int {
+	n := 0
+	for ; access != nil; access = access.AccessData {
+		n++
+	}
+	return n
+}
+
+func TestAccessChainDepth(t *testing.T) {
+	for depth, want := range map[int]int{0: 1, 1: 2, 3: 4, 10: 5} {
+		s := newStore(t, WithAccessChainDepth(depth))
+		client := &osin.DefaultClient{Id: "chain", Secret: "secret", RedirectUri: "http://localhost/"}
+		createClient(t, s, client)
+		last := saveChain(t, s, client, 5)
+
+		access, err := s.LoadAccess(last.AccessToken)
+		require.Nil(t, err)
+		require.Equal(t, want, chainLength(access), "depth %d", depth)
+		refreshed, err := s.LoadRefresh(last.RefreshToken)
+		require.Nil(t, err)
+		require.Equal(t, want, chainLength(refreshed), "depth %d", depth)
+	}
+}
+
+func TestLoadPrevAccess(t *testing.T) {
+	s := newStore(t, WithAccessChainDepth(0), WithTokenHashing(testPepper))
+	client := &osin.DefaultClient{Id: "chain", Secret: "secret", RedirectUri: "http://localhost/"}
+	createClient(t, s, client)
+	last := saveChain(t, s, client, 5)
+
+	access, err := s.LoadAccess(last.AccessToken)
+	require.Nil(t, err)
+	n := 1
+	for {
+		access, err = s.LoadPrevAccess(access)
+		if err == osin.ErrNotFound {
+			break
+		}
+		require.Nil(t, err)
+		require.Nil(t, access.AccessData)
+		require.Equal(t, client.Id, access.Client.GetId())
+		n++
+	}
+	require.Equal(t, 5, n)
+}
+
+// BenchmarkLoadAccess shows loading access data does not depend on the
+// length of its refresh history.
+func BenchmarkLoadAccess(b *testing.B) {
+	for _, n := range []int{1, 10, 100} {
+		b.Run(fmt.Sprintf("history=%d", n), func(b *testing.B) {
+			s := newStore(b)
+			client := &osin.DefaultClient{Id: "chain", Secret: "secret", RedirectUri: "http://localhost/"}
+			require.Nil(b, s.CreateClient(client))
+			last := saveChain(b, s, client, n)
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := s.LoadAccess(last.AccessToken); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,37 @@
+package boltdb
+
+import (
+	"github.com/RangelReale/osin"
+
+	"github.com/dcalandria/osin-boltdb/model"
+)
+
+// ClientCodec converts clients to and from their stored form, so client types
+// richer than osin.DefaultClient keep their type and fields across
+// CreateClient, UpdateClient and GetClient.
+//
+// The clients DecodeClient returns typically wrap base. They should then
+// implement osin.ClientSecretMatcher when base does, and have an
+// Unwrap() osin.Client method returning base, for UpdateClient to keep the
+// secret hash of a *HashedClient.
+type ClientCodec interface {
+	// EncodeClient records in msg the fields of client osin.Client has no
+	// getter for, usually in msg.Metadata. The other fields of msg are set.
+	EncodeClient(client osin.Client, msg *model.Client) error
+	// DecodeClient returns the client stored as msg. base is the client
+	// GetClient returns without a codec: an *osin.DefaultClient, or a
+	// *HashedClient with secret hashing or rotated secrets, wrapped in a
+	// *RegisteredClient when msg has client metadata.
+	DecodeClient(msg *model.Client, base osin.Client) (osin.Client, error)
+}
+
+// unwrapClient returns the innermost client client wraps.
+func unwrapClient(client osin.Client) osin.Client {
+	for {
+		wrapper, ok := client.(interface{ Unwrap() osin.Client })
+		if !ok {
+			return client
+		}
+		client = wrapper.Unwrap()
+	}
+}
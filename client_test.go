@@ -0,0 +1,107 @@
+package boltdb
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/RangelReale/osin"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dcalandria/osin-boltdb/model"
+)
+
+type ownedClient struct {
+	osin.Client
+	Owner      string
+	GrantTypes []string
+}
+
+func (c *ownedClient) Unwrap() osin.Client {
+	return c.Client
+}
+
+func (c *ownedClient) ClientSecretMatches(secret string) bool {
+	return c.Client.(osin.ClientSecretMatcher).ClientSecretMatches(secret)
+}
+
+type ownedClientCodec struct{}
+
+var errNoOwner = errors.New("no owner")
+
+func (ownedClientCodec) EncodeClient(client osin.Client, msg *model.Client) error {
+	c, ok := client.(*ownedClient)
+	if !ok {
+		return nil
+	}
+	if c.Owner == "" {
+		return errNoOwner
+	}
+	msg.Metadata = map[string][]byte{
+		"owner":       []byte(c.Owner),
+		"grant_types": []byte(strings.Join(c.GrantTypes, " ")),
+	}
+	return nil
+}
+
+func (ownedClientCodec) DecodeClient(msg *model.Client, base osin.Client) (osin.Client, error) {
+	if msg.Metadata == nil {
+		return base, nil
+	}
+	return &ownedClient{
+		Client:     base,
+		Owner:      string(msg.Metadata["owner"]),
+		GrantTypes: strings.Fields(string(msg.Metadata["grant_types"])),
+	}, nil
+}
+
+func TestClientCodec(t *testing.T) {
+	s := newStore(t, WithClientCodec(ownedClientCodec{}))
+	owned := &ownedClient{
+		Client:     &osin.DefaultClient{Id: "owned", Secret: "secret", RedirectUri: "http://localhost/", UserData: "data"},
+		Owner:      "alice",
+		GrantTypes: []string{"authorization_code", "refresh_token"},
+	}
+	createClient(t, s, owned)
+	client, err := s.GetClient("owned")
+	require.Nil(t, err)
+	require.Equal(t, owned, client)
+
+	plain := &osin.DefaultClient{Id: "plain", Secret: "secret", RedirectUri: "http://localhost/"}
+	createClient(t, s, plain)
+	client, err = s.GetClient("plain")
+	require.Nil(t, err)
+	require.Equal(t, plain, client)
+
+	require.Equal(t, errNoOwner, s.CreateClient(&ownedClient{Client: &osin.DefaultClient{Id: "orphan"}}))
+	_, err = s.GetClient("orphan")
+	require.Equal(t, osin.ErrNotFound, err)
+
+	// Without the codec, GetClient returns the base client.
+	client, err = New(s.db).GetClient("owned")
+	require.Nil(t, err)
+	require.Equal(t, owned.Client, client)
+}
+
+func TestClientCodecSecretHashing(t *testing.T) {
+	s := newStore(t, WithClientCodec(ownedClientCodec{}), WithSecretHashing(PBKDF2Hasher(1000)))
+	createClient(t, s, &ownedClient{
+		Client: &osin.DefaultClient{Id: "owned", Secret: "secret", RedirectUri: "http://localhost/"},
+		Owner:  "alice",
+	})
+	requireNotStored(t, s, "secret")
+
+	client, err := s.GetClient("owned")
+	require.Nil(t, err)
+	require.IsType(t, &HashedClient{}, client.(*ownedClient).Client)
+	require.True(t, client.(osin.ClientSecretMatcher).ClientSecretMatches("secret"))
+
+	// Updating a loaded client keeps its secret.
+	client.(*ownedClient).Owner = "bob"
+	require.Nil(t, s.UpdateClient(client))
+	client, err = s.GetClient("owned")
+	require.Nil(t, err)
+	require.Equal(t, "bob", client.(*ownedClient).Owner)
+	require.True(t, client.(osin.ClientSecretMatcher).ClientSecretMatches("secret"))
+	require.False(t, client.(osin.ClientSecretMatcher).ClientSecretMatches("wrong"))
+}
@@ -0,0 +1,424 @@
+package boltdb
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/boltdb/bolt"
+)
+
+// Encrypted values start with a zero byte, which never starts a non-empty
+// protobuf message, followed by the format version, the id of the key (one
+// length byte and the id), the nonce and the AES-GCM sealed record. The
+// bucket name and record key are bound as associated data, so values cannot
+// be moved between records. Version 1 values carry no key id and are
+// decrypted with the key of id "".
+const (
+	encryptedMarker   = 0
+	encryptedVersion1 = 1
+	encryptedVersion  = 2
+	maxKeyIDLength    = 255
+)
+
+var (
+	encryptionKey  = []byte("encryption")
+	keyRotationKey = []byte("key_rotation")
+
+	// encryptedBuckets hold the values encrypted by InitDB when encryption
+	// is enabled on an existing database, and re-encrypted by RotateKeys.
+	// The rotation position records the index of a bucket here, so buckets
+	// are only ever appended.
+	encryptedBuckets = [][]byte{
+		clientBucket,
+		authorizeBucket,
+		accessBucket,
+		refreshBucket,
+		familyBucket,
+		retiredRefreshBucket,
+		registrationBucket,
+		lineageIndexBucket,
+		keysBucket,
+	}
+
+	// ErrEncryptionRequired is returned by InitDB when the database holds
+	// encrypted records but the Storage was created without WithEncryption.
+	ErrEncryptionRequired = errors.New("database requires encryption")
+
+	// ErrNotEncrypted is returned when reading a record stored unencrypted
+	// while encryption is enabled.
+	ErrNotEncrypted = errors.New("record is not encrypted")
+
+	// ErrInvalidKey is returned for encryption keys which are not 256 bits.
+	ErrInvalidKey = errors.New("invalid encryption key")
+
+	// ErrUnknownKey is returned when decrypting a record encrypted with a key
+	// the KeyProvider does not know.
+	ErrUnknownKey = errors.New("unknown encryption key")
+)
+
+// KeyProvider supplies the keys stored records are encrypted with.
+type KeyProvider interface {
+	// ActiveKey returns the id and the 256-bit AES key to encrypt with.
+	ActiveKey() (id string, key []byte, err error)
+	// Key returns the key of the given id, to decrypt with.
+	Key(id string) ([]byte, error)
+}
+
+// FileKeyring is a KeyProvider reading keys from a local file, one per line
+// as an id and a base64 encoded 256-bit key separated by a space. A line
+// holding only a key has the empty id. The last key is the active one.
+type FileKeyring struct {
+	filename string
+
+	mu     sync.RWMutex
+	keys   map[string][]byte
+	active string
+}
+
+// NewFileKeyring reads the keys in filename.
+func NewFileKeyring(filename string) (*FileKeyring, error) {
+	k := &FileKeyring{filename: filename}
+	if err := k.Reload(); err != nil {
+		return nil, err
+	}
+	return k, nil
+}
+
+// Reload reads the keys again, picking up keys added to the file.
+func (k *FileKeyring) Reload() error {
+	data, err := ioutil.ReadFile(k.filename)
+	if err != nil {
+		return err
+	}
+	keys := make(map[string][]byte)
+	active := ""
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		id, encoded := "", fields[0]
+		if len(fields) > 1 {
+			id, encoded = fields[0], fields[1]
+		}
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return err
+		}
+		if len(key) != 32 || len(id) > maxKeyIDLength {
+			return ErrInvalidKey
+		}
+		keys[id], active = key, id
+	}
+	if len(keys) == 0 {
+		return ErrInvalidKey
+	}
+
+	k.mu.Lock()
+	k.keys, k.active = keys, active
+	k.mu.Unlock()
+	return nil
+}
+
+// ActiveKey implements KeyProvider.
+func (k *FileKeyring) ActiveKey() (string, []byte, error) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	return k.active, k.keys[k.active], nil
+}
+
+// Key implements KeyProvider.
+func (k *FileKeyring) Key(id string) ([]byte, error) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	key, ok := k.keys[id]
+	if !ok {
+		return nil, ErrUnknownKey
+	}
+	return key, nil
+}
+
+// GenerateKeyFile appends a new random key of the given id to filename,
+// creating it if needed. The new key becomes the active key of a FileKeyring
+// reading the file.
+func GenerateKeyFile(filename string, id string) error {
+	if strings.ContainsAny(id, " \t\n") || len(id) > maxKeyIDLength {
+		return ErrInvalidKey
+	}
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(filename, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(f, "%s %s\n", id, base64.StdEncoding.EncodeToString(key))
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+func associatedData(bucket []byte, key []byte) []byte {
+	ad := make([]byte, 0, len(bucket)+1+len(key))
+	ad = append(ad, bucket...)
+	ad = append(ad, 0)
+	return append(ad, key...)
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	if len(key) != 32 {
+		return nil, ErrInvalidKey
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// seal encrypts the value of key in bucket with the active key. Empty values
+// are kept as is.
+func (s *Storage) seal(bucket []byte, key []byte, value []byte) ([]byte, error) {
+	if len(value) == 0 {
+		return value, nil
+	}
+	id, k, err := s.keys.ActiveKey()
+	if err != nil {
+		return nil, err
+	}
+	if len(id) > maxKeyIDLength {
+		return nil, ErrInvalidKey
+	}
+	aead, err := newAEAD(k)
+	if err != nil {
+		return nil, err
+	}
+	header := 3 + len(id)
+	out := make([]byte, header+aead.NonceSize(), header+aead.NonceSize()+len(value)+aead.Overhead())
+	out[0], out[1], out[2] = encryptedMarker, encryptedVersion, byte(len(id))
+	copy(out[3:], id)
+	nonce := out[header:]
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(out, nonce, value, associatedData(bucket, key)), nil
+}
+
+// keyID returns the id of the key value was encrypted with, and the offset
+// of its nonce.
+func keyID(value []byte) (id string, offset int, err error) {
+	if len(value) < 2 || value[0] != encryptedMarker {
+		return "", 0, ErrNotEncrypted
+	}
+	switch value[1] {
+	case encryptedVersion1:
+		return "", 2, nil
+	case encryptedVersion:
+		if len(value) < 3 || len(value) < 3+int(value[2]) {
+			return "", 0, ErrNotEncrypted
+		}
+		return string(value[3 : 3+int(value[2])]), 3 + int(value[2]), nil
+	}
+	return "", 0, ErrNotEncrypted
+}
+
+// open decrypts a value sealed for key in bucket.
+func (s *Storage) open(bucket []byte, key []byte, value []byte) ([]byte, error) {
+	if len(value) == 0 {
+		return value, nil
+	}
+	id, offset, err := keyID(value)
+	if err != nil {
+		return nil, err
+	}
+	k, err := s.keys.Key(id)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := newAEAD(k)
+	if err != nil {
+		return nil, err
+	}
+	if len(value) < offset+aead.NonceSize() {
+		return nil, ErrNotEncrypted
+	}
+	nonce, sealed := value[offset:offset+aead.NonceSize()], value[offset+aead.NonceSize():]
+	return aead.Open(nil, nonce, sealed, associatedData(bucket, key))
+}
+
+func encrypted(value []byte) bool {
+	_, _, err := keyID(value)
+	return err == nil
+}
+
+// initEncryption checks the database matches the encryption setting,
+// encrypting the existing records the first time it is enabled.
+func (s *Storage) initEncryption(tx *bolt.Tx) error {
+	meta := tx.Bucket(metaBucket)
+	if meta.Get(encryptionKey) != nil {
+		if s.keys == nil {
+			return ErrEncryptionRequired
+		}
+		return nil
+	}
+	if s.keys == nil {
+		return nil
+	}
+	for _, bucket := range encryptedBuckets {
+		// The index buckets InitDB creates afterwards are filled encrypted.
+		b := tx.Bucket(bucket)
+		if b == nil {
+			continue
+		}
+		for _, k := range keys(tx, bucket) {
+			value := b.Get(k)
+			if encrypted(value) {
+				continue
+			}
+			sealed, err := s.seal(bucket, k, value)
+			if err != nil {
+				return err
+			}
+			if err := b.Put(k, sealed); err != nil {
+				return err
+			}
+		}
+	}
+	return meta.Put(encryptionKey, []byte{encryptedVersion})
+}
+
+// RotationProgress reports the progress of RotateKeys.
+type RotationProgress struct {
+	// KeyID is the id of the key records are re-encrypted with.
+	KeyID string
+	// Bucket is the bucket being walked.
+	Bucket string
+	// Scanned and Rotated count the records visited and re-encrypted so far
+	// by this call, across all buckets.
+	Scanned int
+	Rotated int
+}
+
+// RotateKeys re-encrypts with the active key every record encrypted with
+// another key. It works in small write transactions, so the Storage remains
+// usable meanwhile, calling progress, if not nil, after each of them. The
+// position reached is recorded with every transaction: after a crash,
+// RotateKeys resumes from there as long as the active key is unchanged.
+func (s *Storage) RotateKeys(progress func(RotationProgress)) error {
+	if s.keys == nil {
+		return ErrEncryptionRequired
+	}
+	active, _, err := s.keys.ActiveKey()
+	if err != nil {
+		return err
+	}
+
+	p := RotationProgress{KeyID: active}
+	start, from := 0, []byte(nil)
+	err = s.db.View(func(tx *bolt.Tx) error {
+		start, from = loadRotationPosition(tx, active)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for i := start; i < len(encryptedBuckets); i++ {
+		bucket := encryptedBuckets[i]
+		p.Bucket = string(bucket)
+		for {
+			var next []byte
+			err := s.db.Update(func(tx *bolt.Tx) error {
+				b := tx.Bucket(bucket)
+				c := b.Cursor()
+				k, v := c.First()
+				if from != nil {
+					k, v = c.Seek(from)
+				}
+				type record struct{ key, value []byte }
+				var stale []record
+				for n := 0; k != nil; k, v = c.Next() {
+					if n == sweepBatchSize {
+						next = append([]byte(nil), k...)
+						break
+					}
+					n++
+					p.Scanned++
+					if id, _, err := keyID(v); err == nil && id == active || len(v) == 0 {
+						continue
+					}
+					stale = append(stale, record{append([]byte(nil), k...), append([]byte(nil), v...)})
+				}
+				for _, r := range stale {
+					value, err := s.open(bucket, r.key, r.value)
+					if err != nil {
+						return err
+					}
+					value, err = s.seal(bucket, r.key, value)
+					if err != nil {
+						return err
+					}
+					if err := b.Put(r.key, value); err != nil {
+						return err
+					}
+				}
+				p.Rotated += len(stale)
+				if next == nil {
+					return saveRotationPosition(tx, active, i+1, nil)
+				}
+				return saveRotationPosition(tx, active, i, next)
+			})
+			if err != nil {
+				return err
+			}
+			if progress != nil {
+				progress(p)
+			}
+			from = next
+			if next == nil {
+				break
+			}
+		}
+	}
+
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(metaBucket).Delete(keyRotationKey)
+	})
+}
+
+// The rotation position is stored as the length of the key id, the key id,
+// the index of the bucket in encryptedBuckets and the next record key.
+
+func saveRotationPosition(tx *bolt.Tx, id string, bucket int, from []byte) error {
+	pos := make([]byte, 0, 2+len(id)+len(from))
+	pos = append(pos, byte(len(id)))
+	pos = append(pos, id...)
+	pos = append(pos, byte(bucket))
+	pos = append(pos, from...)
+	return tx.Bucket(metaBucket).Put(keyRotationKey, pos)
+}
+
+func loadRotationPosition(tx *bolt.Tx, id string) (bucket int, from []byte) {
+	pos := tx.Bucket(metaBucket).Get(keyRotationKey)
+	if len(pos) < 2+len(id) || int(pos[0]) != len(id) || string(pos[1:1+len(id)]) != id {
+		return 0, nil
+	}
+	pos = pos[1+len(id):]
+	if len(pos[1:]) > 0 {
+		from = append([]byte(nil), pos[1:]...)
+	}
+	return int(pos[0]), from
+}
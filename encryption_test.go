@@ -0,0 +1,205 @@
+package boltdb
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/RangelReale/osin"
+	"github.com/boltdb/bolt"
+	"github.com/pborman/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func newKeyFile(t *testing.T, ids ...string) string {
+	filename := path.Join(os.TempDir(), randomFilename(10)+".key")
+	t.Cleanup(func() {
+		os.Remove(filename)
+	})
+	for _, id := range ids {
+		require.Nil(t, GenerateKeyFile(filename, id))
+	}
+	return filename
+}
+
+func newKeyring(t *testing.T) *FileKeyring {
+	keys, err := NewFileKeyring(newKeyFile(t, "k1"))
+	require.Nil(t, err)
+	return keys
+}
+
+func TestFileKeyring(t *testing.T) {
+	filename := newKeyFile(t)
+	require.Nil(t, ioutil.WriteFile(filename, []byte("c2hvcnQ=\n"), 0600))
+	_, err := NewFileKeyring(filename)
+	require.Equal(t, ErrInvalidKey, err)
+
+	require.Nil(t, ioutil.WriteFile(filename, nil, 0600))
+	require.Nil(t, GenerateKeyFile(filename, "k1"))
+	require.Nil(t, GenerateKeyFile(filename, "k2"))
+	keys, err := NewFileKeyring(filename)
+	require.Nil(t, err)
+	id, key, err := keys.ActiveKey()
+	require.Nil(t, err)
+	require.Equal(t, "k2", id)
+	require.Len(t, key, 32)
+	_, err = keys.Key("k1")
+	require.Nil(t, err)
+	_, err = keys.Key("k3")
+	require.Equal(t, ErrUnknownKey, err)
+}
+
+func TestEncryption(t *testing.T) {
+	s := newStore(t, WithEncryption(newKeyring(t)))
+	client := &osin.DefaultClient{Id: "encrypted", Secret: "client-secret", RedirectUri: "http://localhost/", UserData: "client-user-data"}
+	createClient(t, s, client)
+	getClient(t, s, client)
+
+	access := &osin.AccessData{Client: client, AccessToken: uuid.New(), RefreshToken: uuid.New(), ExpiresIn: 60, CreatedAt: time.Now(), UserData: "access-user-data"}
+	other := &osin.AccessData{Client: client, AccessToken: uuid.New(), ExpiresIn: 60, CreatedAt: time.Now(), UserData: "other-user-data"}
+	require.Nil(t, s.SaveAccess(access))
+	require.Nil(t, s.SaveAccess(other))
+	requireNotStored(t, s, "client-secret", "client-user-data", "access-user-data")
+
+	loaded, err := s.LoadRefresh(access.RefreshToken)
+	require.Nil(t, err)
+	require.Equal(t, "access-user-data", loaded.UserData)
+
+	// A value moved to another key does not decrypt.
+	require.Nil(t, s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(accessBucket)
+		return b.Put([]byte(other.AccessToken), b.Get([]byte(access.AccessToken)))
+	}))
+	_, err = s.LoadAccess(other.AccessToken)
+	require.NotNil(t, err)
+}
+
+func TestEncryptionMigration(t *testing.T) {
+	plain := newStore(t)
+	client := &osin.DefaultClient{Id: "encrypted", Secret: "client-secret", RedirectUri: "http://localhost/"}
+	createClient(t, plain, client)
+	access := &osin.AccessData{Client: client, AccessToken: uuid.New(), RefreshToken: uuid.New(), ExpiresIn: 60, CreatedAt: time.Now(), UserData: "access-user-data"}
+	require.Nil(t, plain.SaveAccess(access))
+
+	s := New(plain.db, WithEncryption(newKeyring(t)), WithTokenHashing(testPepper))
+	require.Nil(t, s.InitDB())
+	requireNotStored(t, s, "client-secret", "access-user-data", access.AccessToken, access.RefreshToken)
+
+	loaded, err := s.LoadRefresh(access.RefreshToken)
+	require.Nil(t, err)
+	require.Equal(t, "access-user-data", loaded.UserData)
+
+	require.Equal(t, ErrEncryptionRequired, New(plain.db, WithTokenHashing(testPepper)).InitDB())
+}
+
+// requireKeyID fails unless every non-empty record is encrypted with key id.
+func requireKeyID(t *testing.T, s *Storage, id string) {
+	require.Nil(t, s.db.View(func(tx *bolt.Tx) error {
+		for _, bucket := range encryptedBuckets {
+			err := tx.Bucket(bucket).ForEach(func(k, v []byte) error {
+				if len(v) > 0 {
+					got, _, err := keyID(v)
+					require.Nil(t, err)
+					require.Equal(t, id, got)
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}))
+}
+
+func TestRotateKeys(t *testing.T) {
+	filename := newKeyFile(t, "k1")
+	keys, err := NewFileKeyring(filename)
+	require.Nil(t, err)
+	s := newStore(t, WithEncryption(keys))
+
+	client := &osin.DefaultClient{Id: "rotated", Secret: "secret", RedirectUri: "http://localhost/"}
+	createClient(t, s, client)
+	var tokens []string
+	for i := 0; i < sweepBatchSize+10; i++ {
+		access := &osin.AccessData{Client: client, AccessToken: uuid.New(), RefreshToken: uuid.New(), ExpiresIn: 60, CreatedAt: time.Now()}
+		require.Nil(t, s.SaveAccess(access))
+		tokens = append(tokens, access.RefreshToken)
+	}
+	requireKeyID(t, s, "k1")
+
+	require.Nil(t, GenerateKeyFile(filename, "k2"))
+	require.Nil(t, keys.Reload())
+
+	// Crash after the first batch.
+	func() {
+		defer func() {
+			require.NotNil(t, recover())
+		}()
+		s.RotateKeys(func(p RotationProgress) {
+			panic("crash")
+		})
+	}()
+	_, err = s.LoadRefresh(tokens[0])
+	require.Nil(t, err)
+
+	var last RotationProgress
+	require.Nil(t, s.RotateKeys(func(p RotationProgress) {
+		last = p
+	}))
+	require.Equal(t, "k2", last.KeyID)
+	// The client bucket, walked in the first batch, is not visited again;
+	// every token has an access, a refresh and a family record, and the
+	// database has its subject key.
+	require.Equal(t, 3*len(tokens)+1, last.Scanned)
+	requireKeyID(t, s, "k2")
+
+	// The old key is no longer needed.
+	data, err := ioutil.ReadFile(filename)
+	require.Nil(t, err)
+	require.Nil(t, ioutil.WriteFile(filename, data[len(data)/2:], 0600))
+	require.Nil(t, keys.Reload())
+	_, err = keys.Key("k1")
+	require.Equal(t, ErrUnknownKey, err)
+	for _, token := range tokens {
+		_, err = s.LoadRefresh(token)
+		require.Nil(t, err)
+	}
+}
+
+func TestRotateKeysLineage(t *testing.T) {
+	plain := newStore(t)
+	client := &osin.DefaultClient{Id: "rotated", Secret: "secret", RedirectUri: "http://localhost/"}
+	createClient(t, plain, client)
+	root := &osin.AccessData{Client: client, AccessToken: uuid.New(), RefreshToken: uuid.New(), ExpiresIn: 60, CreatedAt: time.Now()}
+	require.Nil(t, plain.SaveAccess(root))
+	child := &osin.AccessData{Client: client, AccessData: root, AccessToken: uuid.New(), RefreshToken: uuid.New(), ExpiresIn: 60, CreatedAt: time.Now()}
+	require.Nil(t, plain.SaveAccess(child))
+
+	// The lineage entries stored before encryption is enabled are encrypted
+	// by InitDB, and those stored after it are encrypted too.
+	filename := newKeyFile(t, "k1")
+	keys, err := NewFileKeyring(filename)
+	require.Nil(t, err)
+	s := New(plain.db, WithEncryption(keys))
+	require.Nil(t, s.InitDB())
+	grandchild := &osin.AccessData{Client: client, AccessData: child, AccessToken: uuid.New(), RefreshToken: uuid.New(), ExpiresIn: 60, CreatedAt: time.Now()}
+	require.Nil(t, s.SaveAccess(grandchild))
+	require.Nil(t, s.RemoveAccess(root.AccessToken))
+	requireKeyID(t, s, "k1")
+
+	require.Nil(t, GenerateKeyFile(filename, "k2"))
+	require.Nil(t, keys.Reload())
+	require.Nil(t, s.RotateKeys(nil))
+	requireKeyID(t, s, "k2")
+
+	data, err := ioutil.ReadFile(filename)
+	require.Nil(t, err)
+	require.Nil(t, ioutil.WriteFile(filename, data[len(data)/2:], 0600))
+	require.Nil(t, keys.Reload())
+	lineage, err := s.Lineage(grandchild.AccessToken)
+	require.Nil(t, err)
+	require.Equal(t, []string{child.AccessToken, root.AccessToken}, lineage.Ancestors)
+}
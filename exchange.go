@@ -0,0 +1,162 @@
+package boltdb
+
+import (
+	"github.com/RangelReale/osin"
+	"github.com/boltdb/bolt"
+
+	"github.com/dcalandria/osin-boltdb/model"
+	"github.com/dcalandria/osin-boltdb/storage"
+)
+
+// ExchangeAuthorizeCode consumes the authorization code and saves the access
+// data issued for it, in a single transaction. The consumed code is kept, up
+// to its expiry, to detect its reuse: presenting it again, here or to
+// LoadAuthorize, revokes the tokens issued from it and returns
+// storage.ErrCodeReused, as recommended by RFC 6749 section 4.1.2.
+func (s *Storage) ExchangeAuthorizeCode(code string, access *osin.AccessData) error {
+	key := s.tokenKey(code)
+	used := false
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		msg := &model.AuthorizeData{}
+		err := s.get(tx, authorizeBucket, []byte(key), msg)
+		if err != nil {
+			return err
+		}
+		if msg.Used {
+			used = true
+			return nil
+		}
+		if s.expired(expiresAt(msg.CreatedAt, msg.ExpiresIn)) {
+			return storage.ErrExpired
+		}
+
+		accessMsg, err := s.putAccess(tx, access, s.insert)
+		if err != nil {
+			return err
+		}
+		err = s.putRefresh(tx, accessMsg, s.insert)
+		if err != nil {
+			return err
+		}
+		msg.Used = true
+		msg.AccessTokens = append(msg.AccessTokens, accessMsg.AccessToken)
+		return s.put(tx, authorizeBucket, []byte(key), msg)
+	})
+	if err != nil {
+		return err
+	}
+	if used {
+		return s.codeReused(key)
+	}
+	return nil
+}
+
+// codeReused revokes the tokens issued from a consumed code, along with the
+// tokens refreshed from them and the rest of their refresh families, and
+// returns storage.ErrCodeReused.
+func (s *Storage) codeReused(code string) error {
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		msg := &model.AuthorizeData{}
+		err := s.get(tx, authorizeBucket, []byte(code), msg)
+		if err != nil {
+			return err
+		}
+		families := make(map[string]bool)
+		for _, issued := range msg.AccessTokens {
+			for _, token := range append([]string{issued}, s.descendants(tx, issued)...) {
+				access := &model.AccessData{}
+				if s.get(tx, accessBucket, []byte(token), access) == nil && access.FamilyId != "" {
+					families[access.FamilyId] = true
+				}
+				if err := s.revokeAccess(tx, token); err != nil {
+					return err
+				}
+			}
+		}
+		for family := range families {
+			if err := s.endFamily(tx, family); err != nil {
+				return err
+			}
+			if _, err := s.deleteIndexed(tx, familyIndexBucket, family); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return storage.ErrCodeReused
+}
+
+// revokeAccess deletes the access data and its refresh token.
+func (s *Storage) revokeAccess(tx *bolt.Tx, token string) error {
+	msg := &model.AccessData{}
+	if s.get(tx, accessBucket, []byte(token), msg) != nil {
+		return nil
+	}
+	if msg.RefreshToken != "" {
+		var accessToken []byte
+		if s.get(tx, refreshBucket, []byte(msg.RefreshToken), &accessToken) == nil && string(accessToken) == token {
+			if err := s.deleteRefresh(tx, msg.RefreshToken); err != nil {
+				return err
+			}
+		}
+	}
+	return s.deleteAccess(tx, token)
+}
+
+// consumed reports whether the code was consumed by ExchangeAuthorizeCode.
+func (s *Storage) consumed(code string) bool {
+	used := false
+	s.db.View(func(tx *bolt.Tx) error {
+		msg := &model.AuthorizeData{}
+		if s.get(tx, authorizeBucket, []byte(s.removeKey(code)), msg) == nil {
+			used = msg.Used
+		}
+		return nil
+	})
+	return used
+}
+
+// ExchangeStorage is an osin.Storage routing the authorization code exchange
+// osin performs, LoadAuthorize, SaveAccess then RemoveAuthorize, through
+// ExchangeAuthorizeCode, so a code is never exchanged twice.
+type ExchangeStorage struct {
+	*Storage
+	cloned bool
+}
+
+// NewExchangeStorage wraps s.
+func NewExchangeStorage(s *Storage) *ExchangeStorage {
+	return &ExchangeStorage{Storage: s}
+}
+
+// Clone returns a view of the ExchangeStorage sharing its resources.
+func (e *ExchangeStorage) Clone() osin.Storage {
+	return &ExchangeStorage{Storage: e.Storage, cloned: true}
+}
+
+// Close closes the wrapped Storage, unless called on a clone.
+func (e *ExchangeStorage) Close() {
+	if !e.cloned {
+		e.Storage.Close()
+	}
+}
+
+// SaveAccess exchanges the authorization code of access data issued for one.
+func (e *ExchangeStorage) SaveAccess(access *osin.AccessData) error {
+	if access.AuthorizeData != nil {
+		return e.ExchangeAuthorizeCode(access.AuthorizeData.Code, access)
+	}
+	return e.Storage.SaveAccess(access)
+}
+
+// RemoveAuthorize keeps the codes consumed by SaveAccess, to detect their
+// reuse until they expire.
+func (e *ExchangeStorage) RemoveAuthorize(code string) error {
+	if e.consumed(code) {
+		return nil
+	}
+	return e.Storage.RemoveAuthorize(code)
+}
@@ -0,0 +1,179 @@
+package boltdb
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/RangelReale/osin"
+	"github.com/pborman/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dcalandria/osin-boltdb/storage"
+)
+
+func TestExchangeAuthorizeCode(t *testing.T) {
+	s := newStore(t)
+	client := &osin.DefaultClient{Id: "exchange", Secret: "secret", RedirectUri: "http://localhost/"}
+	createClient(t, s, client)
+	authorize := &osin.AuthorizeData{Client: client, Code: uuid.New(), ExpiresIn: 60, CreatedAt: time.Now()}
+	require.Nil(t, s.SaveAuthorize(authorize))
+
+	access := &osin.AccessData{Client: client, AuthorizeData: authorize, AccessToken: uuid.New(), RefreshToken: uuid.New(), ExpiresIn: 60, CreatedAt: time.Now()}
+	require.Nil(t, s.ExchangeAuthorizeCode(authorize.Code, access))
+	_, err := s.LoadAccess(access.AccessToken)
+	require.Nil(t, err)
+
+	// Reuse revokes the tokens issued from the code.
+	replay := &osin.AccessData{Client: client, AuthorizeData: authorize, AccessToken: uuid.New(), ExpiresIn: 60, CreatedAt: time.Now()}
+	require.Equal(t, storage.ErrCodeReused, s.ExchangeAuthorizeCode(authorize.Code, replay))
+	_, err = s.LoadAccess(replay.AccessToken)
+	require.Equal(t, osin.ErrNotFound, err)
+	_, err = s.LoadAccess(access.AccessToken)
+	require.Equal(t, osin.ErrNotFound, err)
+	_, err = s.LoadRefresh(access.RefreshToken)
+	require.Equal(t, osin.ErrNotFound, err)
+	_, err = s.LoadAuthorize(authorize.Code)
+	require.Equal(t, storage.ErrCodeReused, err)
+
+	require.Equal(t, osin.ErrNotFound, s.ExchangeAuthorizeCode(uuid.New(), replay))
+}
+
+func TestLoadAuthorizeDetectsReuse(t *testing.T) {
+	s := newStore(t, WithTokenHashing(testPepper))
+	client := &osin.DefaultClient{Id: "exchange", Secret: "secret", RedirectUri: "http://localhost/"}
+	createClient(t, s, client)
+	authorize := &osin.AuthorizeData{Client: client, Code: uuid.New(), ExpiresIn: 60, CreatedAt: time.Now()}
+	require.Nil(t, s.SaveAuthorize(authorize))
+	access := &osin.AccessData{Client: client, AuthorizeData: authorize, AccessToken: uuid.New(), ExpiresIn: 60, CreatedAt: time.Now()}
+	require.Nil(t, s.ExchangeAuthorizeCode(authorize.Code, access))
+
+	loaded, err := s.LoadAccess(access.AccessToken)
+	require.Nil(t, err)
+	require.NotNil(t, loaded.AuthorizeData)
+
+	_, err = s.LoadAuthorize(authorize.Code)
+	require.Equal(t, storage.ErrCodeReused, err)
+	_, err = s.LoadAccess(access.AccessToken)
+	require.Equal(t, osin.ErrNotFound, err)
+}
+
+func TestCodeReuseAfterRefresh(t *testing.T) {
+	s := newStore(t, WithTokenHashing(testPepper))
+	client := &osin.DefaultClient{Id: "exchange", Secret: "secret", RedirectUri: "http://localhost/"}
+	createClient(t, s, client)
+	authorize := &osin.AuthorizeData{Client: client, Code: uuid.New(), ExpiresIn: 60, CreatedAt: time.Now()}
+	require.Nil(t, s.SaveAuthorize(authorize))
+	access := &osin.AccessData{Client: client, AuthorizeData: authorize, AccessToken: uuid.New(), RefreshToken: uuid.New(), ExpiresIn: 60, CreatedAt: time.Now()}
+	require.Nil(t, s.ExchangeAuthorizeCode(authorize.Code, access))
+
+	// Refreshing removes the previous access data and refresh token, as osin
+	// does.
+	refresh := func(prev *osin.AccessData) *osin.AccessData {
+		next := &osin.AccessData{Client: client, AccessData: prev, AccessToken: uuid.New(), RefreshToken: uuid.New(), ExpiresIn: 60, CreatedAt: time.Now()}
+		require.Nil(t, s.SaveAccess(next))
+		require.Nil(t, s.RemoveRefresh(prev.RefreshToken))
+		require.Nil(t, s.RemoveAccess(prev.AccessToken))
+		return next
+	}
+	next := refresh(refresh(access))
+	// Access data issued from a refresh token without one of its own.
+	sibling := &osin.AccessData{Client: client, AccessData: next, AccessToken: uuid.New(), ExpiresIn: 60, CreatedAt: time.Now()}
+	require.Nil(t, s.SaveAccess(sibling))
+
+	_, err := s.LoadAuthorize(authorize.Code)
+	require.Equal(t, storage.ErrCodeReused, err)
+	_, err = s.LoadAccess(next.AccessToken)
+	require.Equal(t, osin.ErrNotFound, err)
+	_, err = s.LoadRefresh(next.RefreshToken)
+	require.Equal(t, osin.ErrNotFound, err)
+	_, err = s.LoadAccess(sibling.AccessToken)
+	require.Equal(t, osin.ErrNotFound, err)
+	n, err := s.CountAccessByClient(client.Id)
+	require.Nil(t, err)
+	require.Equal(t, 0, n)
+}
+
+func TestConcurrentExchange(t *testing.T) {
+	s := newStore(t)
+	client := &osin.DefaultClient{Id: "exchange", Secret: "secret", RedirectUri: "http://localhost/"}
+	createClient(t, s, client)
+	authorize := &osin.AuthorizeData{Client: client, Code: uuid.New(), ExpiresIn: 60, CreatedAt: time.Now()}
+	require.Nil(t, s.SaveAuthorize(authorize))
+
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		errs []error
+	)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			access := &osin.AccessData{Client: client, AuthorizeData: authorize, AccessToken: uuid.New(), ExpiresIn: 60, CreatedAt: time.Now()}
+			err := s.ExchangeAuthorizeCode(authorize.Code, access)
+			mu.Lock()
+			errs = append(errs, err)
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	succeeded := 0
+	for _, err := range errs {
+		if err == nil {
+			succeeded++
+		} else {
+			require.Equal(t, storage.ErrCodeReused, err)
+		}
+	}
+	require.Equal(t, 1, succeeded)
+	n, err := s.CountAccessByClient(client.Id)
+	require.Nil(t, err)
+	require.Equal(t, 0, n)
+}
+
+func TestExchangeStorage(t *testing.T) {
+	s := newStore(t)
+	client := &osin.DefaultClient{Id: "exchange", Secret: "secret", RedirectUri: "http://localhost/"}
+	createClient(t, s, client)
+	authorize := &osin.AuthorizeData{Client: client, Code: uuid.New(), ExpiresIn: 60, RedirectUri: "http://localhost/", CreatedAt: time.Now()}
+	require.Nil(t, s.SaveAuthorize(authorize))
+
+	server := osin.NewServer(osin.NewServerConfig(), NewExchangeStorage(s))
+	exchange := func() *osin.Response {
+		form := url.Values{
+			"grant_type":   {"authorization_code"},
+			"code":         {authorize.Code},
+			"redirect_uri": {"http://localhost/"},
+		}
+		req, err := http.NewRequest("POST", "http://localhost/token", strings.NewReader(form.Encode()))
+		require.Nil(t, err)
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.SetBasicAuth(client.Id, client.Secret)
+
+		resp := server.NewResponse()
+		defer resp.Close()
+		if ar := server.HandleAccessRequest(resp, req); ar != nil {
+			ar.Authorized = true
+			server.FinishAccessRequest(resp, req, ar)
+		}
+		return resp
+	}
+
+	resp := exchange()
+	require.False(t, resp.IsError, "%v", resp.InternalError)
+	token := resp.Output["access_token"].(string)
+	_, err := s.LoadAccess(token)
+	require.Nil(t, err)
+
+	// osin removed the code, but its reuse is still detected.
+	resp = exchange()
+	require.True(t, resp.IsError)
+	require.Equal(t, storage.ErrCodeReused, resp.InternalError)
+	_, err = s.LoadAccess(token)
+	require.Equal(t, osin.ErrNotFound, err)
+}
@@ -0,0 +1,120 @@
+package boltdb
+
+import (
+	"encoding/binary"
+	"time"
+
+	"github.com/boltdb/bolt"
+
+	"github.com/dcalandria/osin-boltdb/model"
+)
+
+// The expiry index buckets hold one empty value per record, keyed by the
+// big-endian unix time at which the record expires followed by its key, so
+// expired records are found in order with a cursor.
+
+// expiresAt returns the expiry time of a record created at createdAt (as
+// encoded by time.Time.MarshalBinary) and valid for expiresIn seconds.
+func expiresAt(createdAt []byte, expiresIn int32) time.Time {
+	c := time.Time{}
+	c.UnmarshalBinary(createdAt)
+	return c.Add(time.Duration(expiresIn) * time.Second)
+}
+
+// expiryKey rounds t up to the second, so a record is never considered
+// expired early.
+func expiryKey(t time.Time, key string) []byte {
+	sec := t.Unix()
+	if t.Nanosecond() > 0 {
+		sec++
+	}
+	k := make([]byte, 8+len(key))
+	binary.BigEndian.PutUint64(k, uint64(sec))
+	copy(k[8:], key)
+	return k
+}
+
+// splitExpiryKey returns the expiry time and the record key of an index key.
+func splitExpiryKey(k []byte) (time.Time, string) {
+	return time.Unix(int64(binary.BigEndian.Uint64(k)), 0), string(k[8:])
+}
+
+func authorizeExpiryKey(msg *model.AuthorizeData) []byte {
+	return expiryKey(expiresAt(msg.CreatedAt, msg.ExpiresIn), msg.Code)
+}
+
+// refreshExpiresAt returns when the refresh token of access data expires: the
+// earliest of the refresh token lifetime, the idle timeout and the maximum
+// family lifetime of its client. The zero time means never.
+func (s *Storage) refreshExpiresAt(tx *bolt.Tx, msg *model.AccessData) time.Time {
+	t := s.policyExpiresAt(tx, msg)
+	if s.refreshLifetime > 0 {
+		if e := expiresAt(msg.CreatedAt, 0).Add(s.refreshLifetime); t.IsZero() || e.Before(t) {
+			t = e
+		}
+	}
+	return t
+}
+
+// policyExpiresAt returns when the refresh policy of the client of access
+// data expires its refresh token: the earliest of the idle timeout and the
+// maximum family lifetime. The zero time means never.
+func (s *Storage) policyExpiresAt(tx *bolt.Tx, msg *model.AccessData) time.Time {
+	var t time.Time
+	limit := func(e time.Time) {
+		if t.IsZero() || e.Before(t) {
+			t = e
+		}
+	}
+	createdAt := expiresAt(msg.CreatedAt, 0)
+	policy := s.refreshPolicy(tx, msg.ClientId)
+	if policy.IdleTimeout > 0 {
+		limit(createdAt.Add(policy.IdleTimeout))
+	}
+	if policy.MaxLifetime > 0 {
+		// Access data saved before families were tracked starts its own.
+		familyCreatedAt := createdAt
+		if msg.FamilyCreatedAt != nil {
+			familyCreatedAt = expiresAt(msg.FamilyCreatedAt, 0)
+		}
+		limit(familyCreatedAt.Add(policy.MaxLifetime))
+	}
+	return t
+}
+
+// accessExpiresAt returns when access data may be purged: once both the
+// access token and its refresh token are expired. The zero time means never,
+// for refresh tokens without a lifetime.
+func (s *Storage) accessExpiresAt(tx *bolt.Tx, msg *model.AccessData) time.Time {
+	t := expiresAt(msg.CreatedAt, msg.ExpiresIn)
+	if msg.RefreshToken != "" {
+		r := s.refreshExpiresAt(tx, msg)
+		if r.IsZero() {
+			return r
+		}
+		if r.After(t) {
+			t = r
+		}
+	}
+	return t
+}
+
+// accessExpiryKey returns nil for access data that never expires.
+func (s *Storage) accessExpiryKey(tx *bolt.Tx, msg *model.AccessData) []byte {
+	t := s.accessExpiresAt(tx, msg)
+	if t.IsZero() {
+		return nil
+	}
+	return expiryKey(t, msg.AccessToken)
+}
+
+// expired reports whether t, allowing for clock skew, is in the past. It is
+// always false unless expiry enforcement is enabled.
+func (s *Storage) expired(t time.Time) bool {
+	return s.enforceExpiry && s.past(t)
+}
+
+// past reports whether t, allowing for clock skew, is in the past.
+func (s *Storage) past(t time.Time) bool {
+	return t.Add(s.clockSkew).Before(s.now())
+}
@@ -0,0 +1,81 @@
+package boltdb
+
+import (
+	"testing"
+	"time"
+
+	"github.com/RangelReale/osin"
+	"github.com/pborman/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dcalandria/osin-boltdb/storage"
+)
+
+type testClock struct {
+	t time.Time
+}
+
+func (c *testClock) now() time.Time {
+	return c.t
+}
+
+func TestEnforceExpiry(t *testing.T) {
+	start := time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)
+	clock := &testClock{start}
+	s := newStore(t, WithClock(clock.now), EnforceExpiry(5*time.Second), WithRefreshTokenLifetime(time.Hour))
+
+	client := &osin.DefaultClient{Id: "expiry", Secret: "secret", RedirectUri: "http://localhost/"}
+	createClient(t, s, client)
+	authorize := &osin.AuthorizeData{Client: client, Code: uuid.New(), ExpiresIn: 60, CreatedAt: start}
+	access := &osin.AccessData{Client: client, AccessToken: uuid.New(), RefreshToken: uuid.New(), ExpiresIn: 60, CreatedAt: start}
+	require.Nil(t, s.SaveAuthorize(authorize))
+	require.Nil(t, s.SaveAccess(access))
+
+	// Within the clock skew allowance.
+	clock.t = start.Add(64 * time.Second)
+	_, err := s.LoadAuthorize(authorize.Code)
+	require.Nil(t, err)
+	_, err = s.LoadAccess(access.AccessToken)
+	require.Nil(t, err)
+	stats, err := s.Sweep()
+	require.Nil(t, err)
+	require.Equal(t, SweepStats{}, stats)
+	_, err = s.LoadAuthorize(authorize.Code)
+	require.Nil(t, err)
+
+	clock.t = start.Add(66 * time.Second)
+	_, err = s.LoadAuthorize(authorize.Code)
+	require.Equal(t, storage.ErrExpired, err)
+	_, err = s.LoadAccess(access.AccessToken)
+	require.Equal(t, storage.ErrExpired, err)
+
+	// The refresh token outlives the access token.
+	_, err = s.LoadRefresh(access.RefreshToken)
+	require.Nil(t, err)
+	stats, err = s.Sweep()
+	require.Nil(t, err)
+	require.Equal(t, SweepStats{Authorize: 1}, stats)
+
+	clock.t = start.Add(time.Hour + 6*time.Second)
+	_, err = s.LoadRefresh(access.RefreshToken)
+	require.Equal(t, storage.ErrExpired, err)
+	stats, err = s.Sweep()
+	require.Nil(t, err)
+	require.Equal(t, SweepStats{Access: 1, Refresh: 1}, stats)
+	_, err = s.LoadRefresh(access.RefreshToken)
+	require.Equal(t, osin.ErrNotFound, err)
+}
+
+func TestExpiryNotEnforcedByDefault(t *testing.T) {
+	s := newStore(t)
+	client := &osin.DefaultClient{Id: "expiry", Secret: "secret", RedirectUri: "http://localhost/"}
+	createClient(t, s, client)
+	past := time.Now().Add(-time.Hour)
+	access := &osin.AccessData{Client: client, AccessToken: uuid.New(), RefreshToken: uuid.New(), ExpiresIn: 60, CreatedAt: past}
+	require.Nil(t, s.SaveAccess(access))
+
+	_, err := s.LoadAccess(access.AccessToken)
+	require.Nil(t, err)
+	_, err = s.LoadRefresh(access.RefreshToken)
+	require.Nil(t, err)
+}
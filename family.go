@@ -0,0 +1,161 @@
+package boltdb
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+
+	"github.com/boltdb/bolt"
+
+	"github.com/dcalandria/osin-boltdb/model"
+	"github.com/dcalandria/osin-boltdb/storage"
+)
+
+// Every refresh token starts a family, inherited by the access data refreshed
+// from it. The family record holds the only refresh token of the family that
+// may be used; the tokens it superseded are kept as tombstones, holding the
+// family id, until the family ends, so their reuse can be detected even after
+// osin removes them. Presenting one revokes the whole family, as recommended
+// by the OAuth 2.0 Security Best Current Practice.
+
+// familyReusedError aborts the transaction saving access data refreshed from a
+// superseded refresh token, so its family can be revoked.
+type familyReusedError struct {
+	family string
+}
+
+func (e *familyReusedError) Error() string {
+	return storage.ErrRefreshTokenReused.Error()
+}
+
+func newFamilyID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// joinFamily sets the family of access data holding a refresh token: the
+// family of the access data it is refreshed from, whose refresh token it
+// supersedes, or a new one. Access data refreshed with a superseded refresh
+// token, consumed, is rejected whether or not it holds a refresh token.
+func (s *Storage) joinFamily(tx *bolt.Tx, msg *model.AccessData, consumed string) error {
+	var family *model.RefreshFamily
+	prev := &model.AccessData{}
+	if msg.PrevAccessToken != "" && s.get(tx, accessBucket, []byte(msg.PrevAccessToken), prev) == nil && prev.FamilyId != "" {
+		if consumed == "" {
+			consumed = prev.RefreshToken
+		}
+		f := &model.RefreshFamily{}
+		if s.get(tx, familyBucket, []byte(prev.FamilyId), f) == nil {
+			if f.RefreshToken != consumed {
+				return &familyReusedError{f.Id}
+			}
+			family = f
+		}
+	}
+	// The previous access data may be removed already, by the request that
+	// superseded the refresh token.
+	if family == nil && consumed != "" {
+		if id := s.retiredFamily(tx, consumed); id != "" {
+			return &familyReusedError{id}
+		}
+	}
+	if msg.RefreshToken == "" {
+		return nil
+	}
+
+	if family != nil {
+		err := s.retireRefresh(tx, family.Id, family.RefreshToken)
+		if err != nil {
+			return err
+		}
+		family.RefreshToken = msg.RefreshToken
+		msg.FamilyId, msg.FamilyCreatedAt = family.Id, prev.FamilyCreatedAt
+		return s.put(tx, familyBucket, []byte(family.Id), family)
+	}
+
+	id, err := newFamilyID()
+	if err != nil {
+		return err
+	}
+	msg.FamilyId, msg.FamilyCreatedAt = id, msg.CreatedAt
+	return s.put(tx, familyBucket, []byte(id), &model.RefreshFamily{
+		Id:           id,
+		ClientId:     msg.ClientId,
+		RefreshToken: msg.RefreshToken,
+	})
+}
+
+// currentRefresh returns the refresh token of the family that may be used, or
+// an empty string if the family ended.
+func (s *Storage) currentRefresh(tx *bolt.Tx, id string) string {
+	family := &model.RefreshFamily{}
+	if s.get(tx, familyBucket, []byte(id), family) != nil {
+		return ""
+	}
+	return family.RefreshToken
+}
+
+// retiredFamily returns the family of a superseded refresh token, or an empty
+// string if the token was never superseded.
+func (s *Storage) retiredFamily(tx *bolt.Tx, token string) string {
+	var family []byte
+	if s.get(tx, retiredRefreshBucket, []byte(token), &family) != nil {
+		return ""
+	}
+	return string(family)
+}
+
+func (s *Storage) retireRefresh(tx *bolt.Tx, family string, token string) error {
+	err := s.put(tx, retiredRefreshBucket, []byte(token), []byte(family))
+	if err != nil {
+		return err
+	}
+	return s.put(tx, familyIndexBucket, indexKey(family, retiredKind, token), nil)
+}
+
+func (s *Storage) deleteRetired(tx *bolt.Tx, family string, token string) error {
+	err := s.delete(tx, retiredRefreshBucket, []byte(token))
+	if err != nil {
+		return err
+	}
+	return s.delete(tx, familyIndexBucket, indexKey(family, retiredKind, token))
+}
+
+// endFamily deletes the family record and its tombstones. The tokens of the
+// family remain valid.
+func (s *Storage) endFamily(tx *bolt.Tx, id string) error {
+	prefix := indexPrefix(id)
+	var retired []string
+	c := tx.Bucket(familyIndexBucket).Cursor()
+	for k, _ := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, _ = c.Next() {
+		if kind, key := splitIndexKey(prefix, k); kind == retiredKind {
+			retired = append(retired, key)
+		}
+	}
+	for _, token := range retired {
+		if err := s.deleteRetired(tx, id, token); err != nil {
+			return err
+		}
+	}
+	return s.delete(tx, familyBucket, []byte(id))
+}
+
+// familyReused revokes every token of the family and returns
+// storage.ErrRefreshTokenReused.
+func (s *Storage) familyReused(id string) error {
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		err := s.endFamily(tx, id)
+		if err != nil {
+			return err
+		}
+		_, err = s.deleteIndexed(tx, familyIndexBucket, id)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	return storage.ErrRefreshTokenReused
+}
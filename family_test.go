@@ -0,0 +1,166 @@
+package boltdb
+
+import (
+	"testing"
+	"time"
+
+	"github.com/RangelReale/osin"
+	"github.com/boltdb/bolt"
+	"github.com/pborman/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dcalandria/osin-boltdb/storage"
+)
+
+// refresh performs the refresh osin does: the access data loaded by the
+// refresh token is replaced by new access data.
+func refresh(t *testing.T, s *Storage, token string, retain bool) *osin.AccessData {
+	prev, err := s.LoadRefresh(token)
+	require.Nil(t, err)
+	access := &osin.AccessData{Client: prev.Client, AccessData: prev, AccessToken: uuid.New(), RefreshToken: uuid.New(), ExpiresIn: 60, CreatedAt: s.now()}
+	require.Nil(t, s.SaveAccess(access))
+	if !retain {
+		require.Nil(t, s.RemoveRefresh(prev.RefreshToken))
+		require.Nil(t, s.RemoveAccess(prev.AccessToken))
+	}
+	return access
+}
+
+// requireNoFamilies fails if a refresh token family or tombstone is stored.
+func requireNoFamilies(t *testing.T, s *Storage) {
+	require.Nil(t, s.db.View(func(tx *bolt.Tx) error {
+		require.Equal(t, 0, tx.Bucket(familyBucket).Stats().KeyN)
+		require.Equal(t, 0, tx.Bucket(retiredRefreshBucket).Stats().KeyN)
+		require.Equal(t, 0, tx.Bucket(familyIndexBucket).Stats().KeyN)
+		return nil
+	}))
+}
+
+func TestRefreshTokenReuse(t *testing.T) {
+	for name, opts := range map[string][]Option{
+		"plain":   nil,
+		"hashing": {WithTokenHashing(testPepper)},
+	} {
+		t.Run(name, func(t *testing.T) {
+			s := newStore(t, opts...)
+			client := &osin.DefaultClient{Id: "family", Secret: "secret", RedirectUri: "http://localhost/"}
+			createClient(t, s, client)
+			first := &osin.AccessData{Client: client, AccessToken: uuid.New(), RefreshToken: uuid.New(), ExpiresIn: 60, CreatedAt: time.Now()}
+			require.Nil(t, s.SaveAccess(first))
+
+			second := refresh(t, s, first.RefreshToken, false)
+			third := refresh(t, s, second.RefreshToken, false)
+
+			_, err := s.LoadRefresh(first.RefreshToken)
+			require.Equal(t, storage.ErrRefreshTokenReused, err)
+			_, err = s.LoadRefresh(third.RefreshToken)
+			require.Equal(t, osin.ErrNotFound, err)
+			_, err = s.LoadAccess(third.AccessToken)
+			require.Equal(t, osin.ErrNotFound, err)
+			requireNoFamilies(t, s)
+			requireNotIndexed(t, s, client.Id)
+		})
+	}
+}
+
+func TestRetainedRefreshTokenReuse(t *testing.T) {
+	s := newStore(t)
+	client := &osin.DefaultClient{Id: "family", Secret: "secret", RedirectUri: "http://localhost/"}
+	createClient(t, s, client)
+	first := &osin.AccessData{Client: client, AccessToken: uuid.New(), RefreshToken: uuid.New(), ExpiresIn: 60, CreatedAt: time.Now()}
+	require.Nil(t, s.SaveAccess(first))
+	second := refresh(t, s, first.RefreshToken, true)
+
+	_, err := s.LoadRefresh(first.RefreshToken)
+	require.Equal(t, storage.ErrRefreshTokenReused, err)
+	for _, access := range []*osin.AccessData{first, second} {
+		_, err = s.LoadAccess(access.AccessToken)
+		require.Equal(t, osin.ErrNotFound, err)
+	}
+	requireNoFamilies(t, s)
+}
+
+func TestConcurrentRefresh(t *testing.T) {
+	s := newStore(t)
+	client := &osin.DefaultClient{Id: "family", Secret: "secret", RedirectUri: "http://localhost/"}
+	createClient(t, s, client)
+	first := &osin.AccessData{Client: client, AccessToken: uuid.New(), RefreshToken: uuid.New(), ExpiresIn: 60, CreatedAt: time.Now()}
+	require.Nil(t, s.SaveAccess(first))
+
+	// Both requests load the refresh token before either saves.
+	prev, err := s.LoadRefresh(first.RefreshToken)
+	require.Nil(t, err)
+	winner := &osin.AccessData{Client: client, AccessData: prev, AccessToken: uuid.New(), RefreshToken: uuid.New(), ExpiresIn: 60, CreatedAt: time.Now()}
+	loser := &osin.AccessData{Client: client, AccessData: prev, AccessToken: uuid.New(), RefreshToken: uuid.New(), ExpiresIn: 60, CreatedAt: time.Now()}
+	require.Nil(t, s.SaveAccess(winner))
+	require.Equal(t, storage.ErrRefreshTokenReused, s.SaveAccess(loser))
+
+	_, err = s.LoadAccess(loser.AccessToken)
+	require.Equal(t, osin.ErrNotFound, err)
+	_, err = s.LoadRefresh(winner.RefreshToken)
+	require.Equal(t, osin.ErrNotFound, err)
+	requireNoFamilies(t, s)
+}
+
+func TestConcurrentRefreshWithoutRefreshToken(t *testing.T) {
+	s := newStore(t)
+	client := &osin.DefaultClient{Id: "family", Secret: "secret", RedirectUri: "http://localhost/"}
+	createClient(t, s, client)
+	first := &osin.AccessData{Client: client, AccessToken: uuid.New(), RefreshToken: uuid.New(), ExpiresIn: 60, CreatedAt: time.Now()}
+	require.Nil(t, s.SaveAccess(first))
+
+	// The winner removes the previous access data before the loser, which
+	// gets no refresh token, saves.
+	prev, err := s.LoadRefresh(first.RefreshToken)
+	require.Nil(t, err)
+	winner := &osin.AccessData{Client: client, AccessData: prev, AccessToken: uuid.New(), RefreshToken: uuid.New(), ExpiresIn: 60, CreatedAt: time.Now()}
+	require.Nil(t, s.SaveAccess(winner))
+	require.Nil(t, s.RemoveRefresh(first.RefreshToken))
+	require.Nil(t, s.RemoveAccess(first.AccessToken))
+	loser := &osin.AccessData{Client: client, AccessData: prev, AccessToken: uuid.New(), ExpiresIn: 60, CreatedAt: time.Now()}
+	require.Equal(t, storage.ErrRefreshTokenReused, s.SaveAccess(loser))
+
+	_, err = s.LoadAccess(loser.AccessToken)
+	require.Equal(t, osin.ErrNotFound, err)
+	_, err = s.LoadRefresh(winner.RefreshToken)
+	require.Equal(t, osin.ErrNotFound, err)
+	requireNoFamilies(t, s)
+}
+
+func TestRemoveRefreshEndsFamily(t *testing.T) {
+	s := newStore(t)
+	client := &osin.DefaultClient{Id: "family", Secret: "secret", RedirectUri: "http://localhost/"}
+	createClient(t, s, client)
+	first := &osin.AccessData{Client: client, AccessToken: uuid.New(), RefreshToken: uuid.New(), ExpiresIn: 60, CreatedAt: time.Now()}
+	require.Nil(t, s.SaveAccess(first))
+	second := refresh(t, s, first.RefreshToken, false)
+
+	require.Nil(t, s.RemoveRefresh(second.RefreshToken))
+	_, err := s.LoadAccess(second.AccessToken)
+	require.Nil(t, err)
+	_, err = s.LoadRefresh(first.RefreshToken)
+	require.Equal(t, osin.ErrNotFound, err)
+
+	require.Nil(t, s.RemoveAccess(second.AccessToken))
+	requireNoFamilies(t, s)
+}
+
+func TestFamilyTokenHashingMigration(t *testing.T) {
+	plain := newStore(t)
+	client := &osin.DefaultClient{Id: "family", Secret: "secret", RedirectUri: "http://localhost/"}
+	createClient(t, plain, client)
+	first := &osin.AccessData{Client: client, AccessToken: uuid.New(), RefreshToken: uuid.New(), ExpiresIn: 60, CreatedAt: time.Now()}
+	require.Nil(t, plain.SaveAccess(first))
+	second := refresh(t, plain, first.RefreshToken, false)
+
+	s := New(plain.db, WithTokenHashing(testPepper))
+	require.Nil(t, s.InitDB())
+	requireNotStored(t, s, first.RefreshToken, second.RefreshToken)
+	third := refresh(t, s, second.RefreshToken, false)
+
+	_, err := s.LoadRefresh(first.RefreshToken)
+	require.Equal(t, storage.ErrRefreshTokenReused, err)
+	_, err = s.LoadAccess(third.AccessToken)
+	require.Equal(t, osin.ErrNotFound, err)
+	requireNoFamilies(t, s)
+}
@@ -0,0 +1,218 @@
+package boltdb
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
+
+	"github.com/boltdb/bolt"
+
+	"github.com/dcalandria/osin-boltdb/model"
+)
+
+// hashedKeyPrefix marks hashed codes and tokens. Data loaded with token
+// hashing enabled carries these keys in place of the raw values it does not
+// know, e.g. the AccessToken of the AccessData returned by LoadRefresh.
+const hashedKeyPrefix = "hmac-sha256:"
+
+var (
+	tokenHashingKey = []byte("token_hashing")
+
+	// ErrTokenHashingRequired is returned by InitDB when the database holds
+	// hashed tokens but the Storage was created without WithTokenHashing.
+	ErrTokenHashingRequired = errors.New("database requires token hashing")
+)
+
+// tokenKey returns the key under which a code or token is stored.
+func (s *Storage) tokenKey(token string) string {
+	if s.pepper == nil || token == "" {
+		return token
+	}
+	mac := hmac.New(sha256.New, s.pepper)
+	mac.Write([]byte(token))
+	return hashedKeyPrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// removeKey is like tokenKey but also accepts an already hashed key, so the
+// data returned by the loaders can be removed.
+func (s *Storage) removeKey(token string) string {
+	if s.pepper != nil && strings.HasPrefix(token, hashedKeyPrefix) {
+		return token
+	}
+	return s.tokenKey(token)
+}
+
+// migrateKey hashes a key stored before token hashing was enabled.
+func (s *Storage) migrateKey(key string) string {
+	if strings.HasPrefix(key, hashedKeyPrefix) {
+		return key
+	}
+	return s.tokenKey(key)
+}
+
+// initTokenHashing checks the database matches the token hashing setting,
+// hashing the keys of existing records the first time it is enabled.
+func (s *Storage) initTokenHashing(tx *bolt.Tx) error {
+	meta := tx.Bucket(metaBucket)
+	if meta.Get(tokenHashingKey) != nil {
+		if s.pepper == nil {
+			return ErrTokenHashingRequired
+		}
+		return nil
+	}
+	if s.pepper == nil {
+		return nil
+	}
+	if err := s.migrateAuthorizeKeys(tx); err != nil {
+		return err
+	}
+	// The lineage index is migrated first, so the migrated access data keeps
+	// its entries.
+	if err := s.migrateLineageKeys(tx); err != nil {
+		return err
+	}
+	if err := s.migrateAccessKeys(tx); err != nil {
+		return err
+	}
+	if err := s.migrateRefreshKeys(tx); err != nil {
+		return err
+	}
+	if err := s.migrateFamilyKeys(tx); err != nil {
+		return err
+	}
+	return meta.Put(tokenHashingKey, []byte(hashedKeyPrefix))
+}
+
+// keys returns a copy of the keys of bucket, so it can be modified while
+// iterating over them.
+func keys(tx *bolt.Tx, bucket []byte) [][]byte {
+	var keys [][]byte
+	tx.Bucket(bucket).ForEach(func(k, v []byte) error {
+		keys = append(keys, append([]byte(nil), k...))
+		return nil
+	})
+	return keys
+}
+
+func (s *Storage) migrateAuthorizeKeys(tx *bolt.Tx) error {
+	for _, k := range keys(tx, authorizeBucket) {
+		msg := &model.AuthorizeData{}
+		if err := s.get(tx, authorizeBucket, k, msg); err != nil {
+			return err
+		}
+		if err := s.deleteAuthorize(tx, string(k)); err != nil {
+			return err
+		}
+		msg.Code = s.migrateKey(msg.Code)
+		for i, token := range msg.AccessTokens {
+			msg.AccessTokens[i] = s.migrateKey(token)
+		}
+		if err := s.put(tx, authorizeBucket, []byte(msg.Code), msg); err != nil {
+			return err
+		}
+		if err := s.indexAuthorize(tx, msg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Storage) migrateAccessKeys(tx *bolt.Tx) error {
+	for _, k := range keys(tx, accessBucket) {
+		msg := &model.AccessData{}
+		if err := s.get(tx, accessBucket, k, msg); err != nil {
+			return err
+		}
+		if err := s.deleteAccess(tx, string(k)); err != nil {
+			return err
+		}
+		msg.AccessToken = s.migrateKey(msg.AccessToken)
+		msg.RefreshToken = s.migrateKey(msg.RefreshToken)
+		msg.AuthorizeCode = s.migrateKey(msg.AuthorizeCode)
+		msg.PrevAccessToken = s.migrateKey(msg.PrevAccessToken)
+		if msg.Origin != nil {
+			msg.Origin.Code = s.migrateKey(msg.Origin.Code)
+		}
+		if err := s.put(tx, accessBucket, []byte(msg.AccessToken), msg); err != nil {
+			return err
+		}
+		if err := s.indexAccess(tx, msg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Storage) migrateLineageKeys(tx *bolt.Tx) error {
+	for _, k := range keys(tx, lineageIndexBucket) {
+		var v []byte
+		if err := s.get(tx, lineageIndexBucket, k, &v); err != nil {
+			return err
+		}
+		if err := s.delete(tx, lineageIndexBucket, k); err != nil {
+			return err
+		}
+		i := bytes.IndexByte(k, 0)
+		prev := string(k[:i])
+		_, token := splitIndexKey(k[:i+1], k)
+		grand, code := splitLineageValue(v)
+		key := indexKey(s.migrateKey(prev), accessKind, s.migrateKey(token))
+		if err := s.put(tx, lineageIndexBucket, key, lineageValue(s.migrateKey(grand), s.migrateKey(code))); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Storage) migrateRefreshKeys(tx *bolt.Tx) error {
+	for _, k := range keys(tx, refreshBucket) {
+		var accessToken []byte
+		if err := s.get(tx, refreshBucket, k, &accessToken); err != nil {
+			return err
+		}
+		// The access data has been migrated already.
+		accessKey, key := s.migrateKey(string(accessToken)), s.migrateKey(string(k))
+		msg := &model.AccessData{}
+		if s.get(tx, accessBucket, []byte(accessKey), msg) == nil {
+			if err := s.unindexRefresh(tx, msg, string(k)); err != nil {
+				return err
+			}
+			if err := s.indexRefresh(tx, msg, key); err != nil {
+				return err
+			}
+		}
+		if err := s.delete(tx, refreshBucket, k); err != nil {
+			return err
+		}
+		if err := s.put(tx, refreshBucket, []byte(key), []byte(accessKey)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Storage) migrateFamilyKeys(tx *bolt.Tx) error {
+	for _, k := range keys(tx, familyBucket) {
+		family := &model.RefreshFamily{}
+		if err := s.get(tx, familyBucket, k, family); err != nil {
+			return err
+		}
+		family.RefreshToken = s.migrateKey(family.RefreshToken)
+		if err := s.put(tx, familyBucket, k, family); err != nil {
+			return err
+		}
+	}
+	for _, k := range keys(tx, retiredRefreshBucket) {
+		family := s.retiredFamily(tx, string(k))
+		if err := s.deleteRetired(tx, family, string(k)); err != nil {
+			return err
+		}
+		if err := s.retireRefresh(tx, family, s.migrateKey(string(k))); err != nil {
+			return err
+		}
+	}
+	return nil
+}
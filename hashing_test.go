@@ -0,0 +1,108 @@
+package boltdb
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/RangelReale/osin"
+	"github.com/boltdb/bolt"
+	"github.com/pborman/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+var testPepper = []byte("pepper")
+
+// requireNotStored fails if any of values appears in a key or value of the
+// database.
+func requireNotStored(t *testing.T, s *Storage, values ...string) {
+	require.Nil(t, s.db.View(func(tx *bolt.Tx) error {
+		return tx.ForEach(func(name []byte, b *bolt.Bucket) error {
+			return b.ForEach(func(k, v []byte) error {
+				for _, value := range values {
+					require.False(t, bytes.Contains(k, []byte(value)), "%s found in bucket %s", value, name)
+					require.False(t, bytes.Contains(v, []byte(value)), "%s found in bucket %s", value, name)
+				}
+				return nil
+			})
+		})
+	}))
+}
+
+func TestTokenHashing(t *testing.T) {
+	s := newStore(t, WithTokenHashing(testPepper))
+	client := &osin.DefaultClient{Id: "hashing", Secret: "secret", RedirectUri: "http://localhost/"}
+	createClient(t, s, client)
+
+	authorize := &osin.AuthorizeData{Client: client, Code: uuid.New(), ExpiresIn: 60, CreatedAt: time.Now()}
+	access := &osin.AccessData{Client: client, AuthorizeData: authorize, AccessToken: uuid.New(), RefreshToken: uuid.New(), ExpiresIn: 60, CreatedAt: time.Now()}
+	require.Nil(t, s.SaveAuthorize(authorize))
+	require.Nil(t, s.SaveAccess(access))
+	requireNotStored(t, s, authorize.Code, access.AccessToken, access.RefreshToken)
+
+	loaded, err := s.LoadAuthorize(authorize.Code)
+	require.Nil(t, err)
+	require.Equal(t, authorize.Code, loaded.Code)
+
+	loadedAccess, err := s.LoadAccess(access.AccessToken)
+	require.Nil(t, err)
+	require.Equal(t, access.AccessToken, loadedAccess.AccessToken)
+	require.NotNil(t, loadedAccess.AuthorizeData)
+
+	// osin removes the access data returned by LoadRefresh, whose raw token
+	// is not known to the store.
+	refreshed, err := s.LoadRefresh(access.RefreshToken)
+	require.Nil(t, err)
+	require.Equal(t, access.RefreshToken, refreshed.RefreshToken)
+	require.NotEqual(t, access.AccessToken, refreshed.AccessToken)
+	require.Nil(t, s.RemoveRefresh(refreshed.RefreshToken))
+	require.Nil(t, s.RemoveAccess(refreshed.AccessToken))
+	_, err = s.LoadAccess(access.AccessToken)
+	require.Equal(t, osin.ErrNotFound, err)
+
+	// A hashed key is not accepted in place of the raw token.
+	_, err = s.LoadAuthorize(s.tokenKey(authorize.Code))
+	require.Equal(t, osin.ErrNotFound, err)
+}
+
+func TestTokenHashingMigration(t *testing.T) {
+	plain := newStore(t)
+	client := &osin.DefaultClient{Id: "hashing", Secret: "secret", RedirectUri: "http://localhost/"}
+	createClient(t, plain, client)
+
+	authorize := &osin.AuthorizeData{Client: client, Code: uuid.New(), ExpiresIn: 60, CreatedAt: time.Now().Add(-time.Hour)}
+	prev := &osin.AccessData{Client: client, AccessToken: uuid.New(), ExpiresIn: 60, CreatedAt: time.Now()}
+	access := &osin.AccessData{Client: client, AuthorizeData: authorize, AccessData: prev, AccessToken: uuid.New(), RefreshToken: uuid.New(), ExpiresIn: 60, CreatedAt: time.Now()}
+	require.Nil(t, plain.SaveAuthorize(authorize))
+	require.Nil(t, plain.SaveAccess(prev))
+	require.Nil(t, plain.SaveAccess(access))
+
+	s := New(plain.db, WithTokenHashing(testPepper))
+	require.Nil(t, s.InitDB())
+	requireNotStored(t, s, authorize.Code, prev.AccessToken, access.AccessToken, access.RefreshToken)
+
+	_, err := s.LoadAuthorize(authorize.Code)
+	require.Nil(t, err)
+	loaded, err := s.LoadRefresh(access.RefreshToken)
+	require.Nil(t, err)
+	require.NotNil(t, loaded.AuthorizeData)
+	require.NotNil(t, loaded.AccessData)
+
+	// Migration is done once.
+	require.Nil(t, s.InitDB())
+	_, err = s.LoadAccess(access.AccessToken)
+	require.Nil(t, err)
+
+	// The expiry index follows the new keys.
+	stats, err := s.Sweep()
+	require.Nil(t, err)
+	require.Equal(t, SweepStats{Authorize: 1}, stats)
+
+	require.Equal(t, ErrTokenHashingRequired, New(plain.db).InitDB())
+
+	// The client index follows the new keys.
+	require.Nil(t, s.RemoveClient(client.Id))
+	_, err = s.LoadRefresh(access.RefreshToken)
+	require.Equal(t, osin.ErrNotFound, err)
+	requireNotIndexed(t, s, client.Id)
+}
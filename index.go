@@ -0,0 +1,289 @@
+package boltdb
+
+import (
+	"bytes"
+	"encoding/binary"
+
+	"github.com/boltdb/bolt"
+
+	"github.com/dcalandria/osin-boltdb/model"
+)
+
+// indexBuckets hold the secondary indexes, maintained along with the records
+// and rebuilt by InitDB when missing.
+var indexBuckets = [][]byte{
+	authorizeExpiryBucket,
+	accessExpiryBucket,
+	clientIndexBucket,
+	subjectIndexBucket,
+	familyIndexBucket,
+	lineageIndexBucket,
+}
+
+// The client, subject and family indexes hold one empty value per record of
+// a client, subject or refresh token family, keyed by the client id, subject
+// key or family id, a zero byte, the kind of record and the record key.
+const (
+	authorizeKind byte = 'a'
+	accessKind    byte = 't'
+	refreshKind   byte = 'r'
+	retiredKind   byte = 'x'
+)
+
+func indexPrefix(owner string) []byte {
+	prefix := make([]byte, 0, len(owner)+1)
+	prefix = append(prefix, owner...)
+	return append(prefix, 0)
+}
+
+func indexKey(owner string, kind byte, key string) []byte {
+	k := make([]byte, 0, len(owner)+2+len(key))
+	k = append(k, indexPrefix(owner)...)
+	k = append(k, kind)
+	return append(k, key...)
+}
+
+// splitIndexKey returns the kind and the record key of an index key with the
+// given prefix.
+func splitIndexKey(prefix []byte, k []byte) (byte, string) {
+	return k[len(prefix)], string(k[len(prefix)+1:])
+}
+
+// putOwnerIndex indexes the record under its client and its subject, if any.
+func (s *Storage) putOwnerIndex(tx *bolt.Tx, clientID string, subject string, kind byte, key string) error {
+	err := s.put(tx, clientIndexBucket, indexKey(clientID, kind, key), nil)
+	if err != nil || subject == "" {
+		return err
+	}
+	subject, err = s.subjectKey(tx, subject)
+	if err != nil {
+		return err
+	}
+	return s.put(tx, subjectIndexBucket, indexKey(subject, kind, key), nil)
+}
+
+func (s *Storage) deleteOwnerIndex(tx *bolt.Tx, clientID string, subject string, kind byte, key string) error {
+	err := s.delete(tx, clientIndexBucket, indexKey(clientID, kind, key))
+	if err != nil || subject == "" {
+		return err
+	}
+	subject, err = s.subjectKey(tx, subject)
+	if err != nil {
+		return err
+	}
+	return s.delete(tx, subjectIndexBucket, indexKey(subject, kind, key))
+}
+
+func (s *Storage) indexAuthorize(tx *bolt.Tx, msg *model.AuthorizeData) error {
+	err := s.put(tx, authorizeExpiryBucket, authorizeExpiryKey(msg), nil)
+	if err != nil {
+		return err
+	}
+	return s.putOwnerIndex(tx, msg.ClientId, s.userDataSubject(msg.UserData), authorizeKind, msg.Code)
+}
+
+func (s *Storage) unindexAuthorize(tx *bolt.Tx, msg *model.AuthorizeData) error {
+	err := s.delete(tx, authorizeExpiryBucket, authorizeExpiryKey(msg))
+	if err != nil {
+		return err
+	}
+	return s.deleteOwnerIndex(tx, msg.ClientId, s.userDataSubject(msg.UserData), authorizeKind, msg.Code)
+}
+
+func (s *Storage) indexAccess(tx *bolt.Tx, msg *model.AccessData) error {
+	if key := s.accessExpiryKey(tx, msg); key != nil {
+		err := s.put(tx, accessExpiryBucket, key, nil)
+		if err != nil {
+			return err
+		}
+	}
+	if msg.FamilyId != "" {
+		err := s.put(tx, familyIndexBucket, indexKey(msg.FamilyId, accessKind, msg.AccessToken), nil)
+		if err != nil {
+			return err
+		}
+	}
+	err := s.linkAccess(tx, msg)
+	if err != nil {
+		return err
+	}
+	return s.putOwnerIndex(tx, msg.ClientId, s.userDataSubject(msg.UserData), accessKind, msg.AccessToken)
+}
+
+func (s *Storage) unindexAccess(tx *bolt.Tx, msg *model.AccessData) error {
+	if key := s.accessExpiryKey(tx, msg); key != nil {
+		err := s.delete(tx, accessExpiryBucket, key)
+		if err != nil {
+			return err
+		}
+	}
+	if msg.FamilyId != "" {
+		err := s.delete(tx, familyIndexBucket, indexKey(msg.FamilyId, accessKind, msg.AccessToken))
+		if err != nil {
+			return err
+		}
+	}
+	return s.deleteOwnerIndex(tx, msg.ClientId, s.userDataSubject(msg.UserData), accessKind, msg.AccessToken)
+}
+
+// indexRefresh indexes a refresh token under the client, subject and family
+// of its access data.
+func (s *Storage) indexRefresh(tx *bolt.Tx, access *model.AccessData, token string) error {
+	if access.FamilyId != "" {
+		err := s.put(tx, familyIndexBucket, indexKey(access.FamilyId, refreshKind, token), nil)
+		if err != nil {
+			return err
+		}
+	}
+	return s.putOwnerIndex(tx, access.ClientId, s.userDataSubject(access.UserData), refreshKind, token)
+}
+
+func (s *Storage) unindexRefresh(tx *bolt.Tx, access *model.AccessData, token string) error {
+	if access.FamilyId != "" {
+		err := s.delete(tx, familyIndexBucket, indexKey(access.FamilyId, refreshKind, token))
+		if err != nil {
+			return err
+		}
+	}
+	return s.deleteOwnerIndex(tx, access.ClientId, s.userDataSubject(access.UserData), refreshKind, token)
+}
+
+// refreshLifetimeKey records the refresh token lifetime the access expiry
+// index was built with.
+var refreshLifetimeKey = []byte("refresh_lifetime")
+
+// initAccessExpiry rebuilds the access expiry index if the refresh token
+// lifetime changed since it was built: the expiry of access data holding a
+// refresh token depends on it, and access data gets no entry without it.
+func (s *Storage) initAccessExpiry(tx *bolt.Tx) error {
+	lifetime := make([]byte, 8)
+	binary.BigEndian.PutUint64(lifetime, uint64(s.refreshLifetime))
+	meta := tx.Bucket(metaBucket)
+	if bytes.Equal(meta.Get(refreshLifetimeKey), lifetime) {
+		return nil
+	}
+
+	if err := tx.DeleteBucket(accessExpiryBucket); err != nil {
+		return err
+	}
+	if _, err := tx.CreateBucket(accessExpiryBucket); err != nil {
+		return err
+	}
+	err := tx.Bucket(accessBucket).ForEach(func(k, v []byte) error {
+		msg := &model.AccessData{}
+		if err := s.get(tx, accessBucket, k, msg); err != nil {
+			return err
+		}
+		if key := s.accessExpiryKey(tx, msg); key != nil {
+			return s.put(tx, accessExpiryBucket, key, nil)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return meta.Put(refreshLifetimeKey, lifetime)
+}
+
+// initIndexes creates the missing index buckets, indexing the existing
+// records if any was missing.
+func (s *Storage) initIndexes(tx *bolt.Tx) error {
+	missing := false
+	for _, bucket := range indexBuckets {
+		if tx.Bucket(bucket) == nil {
+			missing = true
+			if _, err := tx.CreateBucket(bucket); err != nil {
+				return err
+			}
+		}
+	}
+	if !missing {
+		return nil
+	}
+
+	err := tx.Bucket(authorizeBucket).ForEach(func(k, v []byte) error {
+		msg := &model.AuthorizeData{}
+		if err := s.get(tx, authorizeBucket, k, msg); err != nil {
+			return err
+		}
+		return s.indexAuthorize(tx, msg)
+	})
+	if err != nil {
+		return err
+	}
+
+	err = tx.Bucket(accessBucket).ForEach(func(k, v []byte) error {
+		msg := &model.AccessData{}
+		if err := s.get(tx, accessBucket, k, msg); err != nil {
+			return err
+		}
+		return s.indexAccess(tx, msg)
+	})
+	if err != nil {
+		return err
+	}
+
+	err = tx.Bucket(refreshBucket).ForEach(func(k, v []byte) error {
+		var accessToken []byte
+		if err := s.get(tx, refreshBucket, k, &accessToken); err != nil {
+			return err
+		}
+		msg := &model.AccessData{}
+		if s.get(tx, accessBucket, accessToken, msg) != nil {
+			return nil
+		}
+		return s.indexRefresh(tx, msg, string(k))
+	})
+	if err != nil {
+		return err
+	}
+
+	return tx.Bucket(retiredRefreshBucket).ForEach(func(k, v []byte) error {
+		var family []byte
+		if err := s.get(tx, retiredRefreshBucket, k, &family); err != nil {
+			return err
+		}
+		return s.put(tx, familyIndexBucket, indexKey(string(family), retiredKind, string(k)), nil)
+	})
+}
+
+// deleteIndexed deletes every authorize code, access token and refresh token
+// indexed under owner in the index bucket, returning how many were deleted.
+func (s *Storage) deleteIndexed(tx *bolt.Tx, bucket []byte, owner string) (int, error) {
+	prefix := indexPrefix(owner)
+	var keys [][]byte
+	c := tx.Bucket(bucket).Cursor()
+	for k, _ := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, _ = c.Next() {
+		keys = append(keys, append([]byte(nil), k...))
+	}
+
+	n := 0
+	for _, k := range keys {
+		var err error
+		kind, key := splitIndexKey(prefix, k)
+		switch kind {
+		case authorizeKind:
+			err = s.deleteIndexedRecord(tx, authorizeBucket, key, s.deleteAuthorize, &n)
+		case accessKind:
+			err = s.deleteIndexedRecord(tx, accessBucket, key, s.deleteAccess, &n)
+		case refreshKind:
+			err = s.deleteIndexedRecord(tx, refreshBucket, key, s.deleteRefresh, &n)
+		}
+		if err != nil {
+			return n, err
+		}
+		// Stale entries are not removed with their records.
+		if err := s.delete(tx, bucket, k); err != nil {
+			return n, err
+		}
+	}
+	return n, nil
+}
+
+func (s *Storage) deleteIndexedRecord(tx *bolt.Tx, bucket []byte, key string, remove func(*bolt.Tx, string) error, n *int) error {
+	if tx.Bucket(bucket).Get([]byte(key)) == nil {
+		return nil
+	}
+	*n++
+	return remove(tx, key)
+}
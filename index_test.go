@@ -0,0 +1,77 @@
+package boltdb
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/RangelReale/osin"
+	"github.com/boltdb/bolt"
+	"github.com/pborman/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+// requireNotIndexed fails if the client index holds entries for clientID.
+func requireNotIndexed(t *testing.T, s *Storage, clientID string) {
+	prefix := indexPrefix(clientID)
+	require.Nil(t, s.db.View(func(tx *bolt.Tx) error {
+		k, _ := tx.Bucket(clientIndexBucket).Cursor().Seek(prefix)
+		require.False(t, k != nil && bytes.HasPrefix(k, prefix), "%q is indexed", k)
+		return nil
+	}))
+}
+
+func TestRemoveClientCascades(t *testing.T) {
+	s := newStore(t)
+	client := &osin.DefaultClient{Id: "removed", Secret: "secret", RedirectUri: "http://localhost/"}
+	other := &osin.DefaultClient{Id: "kept", Secret: "secret", RedirectUri: "http://localhost/"}
+	createClient(t, s, client)
+	createClient(t, s, other)
+
+	authorize := &osin.AuthorizeData{Client: client, Code: uuid.New(), ExpiresIn: 60, CreatedAt: time.Now()}
+	access := &osin.AccessData{Client: client, AuthorizeData: authorize, AccessToken: uuid.New(), RefreshToken: uuid.New(), ExpiresIn: 60, CreatedAt: time.Now()}
+	orphan := &osin.AccessData{Client: client, AccessToken: uuid.New(), RefreshToken: uuid.New(), ExpiresIn: 60, CreatedAt: time.Now()}
+	kept := &osin.AccessData{Client: other, AccessToken: uuid.New(), RefreshToken: uuid.New(), ExpiresIn: 60, CreatedAt: time.Now()}
+	require.Nil(t, s.SaveAuthorize(authorize))
+	require.Nil(t, s.SaveAccess(access))
+	require.Nil(t, s.SaveAccess(orphan))
+	require.Nil(t, s.SaveAccess(kept))
+	require.Nil(t, s.RemoveAccess(orphan.AccessToken))
+
+	require.Nil(t, s.RemoveClient(client.Id))
+
+	_, err := s.GetClient(client.Id)
+	require.Equal(t, osin.ErrNotFound, err)
+	_, err = s.LoadAuthorize(authorize.Code)
+	require.Equal(t, osin.ErrNotFound, err)
+	_, err = s.LoadAccess(access.AccessToken)
+	require.Equal(t, osin.ErrNotFound, err)
+	_, err = s.LoadRefresh(access.RefreshToken)
+	require.Equal(t, osin.ErrNotFound, err)
+	require.Nil(t, s.db.View(func(tx *bolt.Tx) error {
+		require.Nil(t, tx.Bucket(refreshBucket).Get([]byte(orphan.RefreshToken)))
+		return nil
+	}))
+	requireNotIndexed(t, s, client.Id)
+
+	_, err = s.LoadRefresh(kept.RefreshToken)
+	require.Nil(t, err)
+}
+
+func TestClientIndexIsRebuilt(t *testing.T) {
+	s := newStore(t)
+	client := &osin.DefaultClient{Id: "removed", Secret: "secret", RedirectUri: "http://localhost/"}
+	createClient(t, s, client)
+	access := &osin.AccessData{Client: client, AccessToken: uuid.New(), RefreshToken: uuid.New(), ExpiresIn: 60, CreatedAt: time.Now()}
+	require.Nil(t, s.SaveAccess(access))
+
+	require.Nil(t, s.db.Update(func(tx *bolt.Tx) error {
+		return tx.DeleteBucket(clientIndexBucket)
+	}))
+	require.Nil(t, s.InitDB())
+
+	require.Nil(t, s.RemoveClient(client.Id))
+	_, err := s.LoadRefresh(access.RefreshToken)
+	require.Equal(t, osin.ErrNotFound, err)
+	requireNotIndexed(t, s, client.Id)
+}
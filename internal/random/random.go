@@ -0,0 +1,14 @@
+// Package random generates the random ids, secrets and tokens of the
+// storage and the registration handler.
+package random
+
+import "crypto/rand"
+
+// String returns n random bytes encoded with encode.
+func String(n int, encode func([]byte) string) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return encode(b), nil
+}
@@ -0,0 +1,206 @@
+package boltdb
+
+import (
+	"bytes"
+
+	"github.com/RangelReale/osin"
+	"github.com/boltdb/bolt"
+
+	"github.com/dcalandria/osin-boltdb/model"
+)
+
+// The lineage index holds one entry per access data refreshed from another,
+// keyed like the client index by the previous access token, a zero byte, the
+// kind of record and the access token. Its value holds the access token the
+// previous one was refreshed from and the authorization code the chain
+// started from, as known when the entry was written. An entry outlives its
+// access data as long as descendants of it are indexed, so the chain can be
+// followed through the tokens osin removes when refreshing.
+
+// Lineage tells how access data came to exist.
+type Lineage struct {
+	// Ancestors holds the keys of the access tokens the access data was
+	// refreshed from, the most recent first.
+	Ancestors []string
+	// AuthorizeCode is the key of the authorization code the chain started
+	// from, empty if unknown.
+	AuthorizeCode string
+	// Authorize is the data of AuthorizeCode, with its client, redirect URI
+	// and PKCE challenge, as kept by the access data, or nil if unknown. Its
+	// UserData is nil unless the code is still stored.
+	Authorize *osin.AuthorizeData
+}
+
+func lineageValue(prev string, code string) []byte {
+	return append(indexPrefix(prev), code...)
+}
+
+// splitLineageValue returns the previous access token and the authorization
+// code of a lineage index value.
+func splitLineageValue(v []byte) (string, string) {
+	i := bytes.IndexByte(v, 0)
+	if i < 0 {
+		return "", ""
+	}
+	return string(v[:i]), string(v[i+1:])
+}
+
+func (s *Storage) hasDescendants(tx *bolt.Tx, token string) bool {
+	prefix := indexPrefix(token)
+	k, _ := tx.Bucket(lineageIndexBucket).Cursor().Seek(prefix)
+	return k != nil && bytes.HasPrefix(k, prefix)
+}
+
+// originCode returns the key of the authorization code the chain of the
+// access data started from.
+func (s *Storage) originCode(tx *bolt.Tx, msg *model.AccessData) string {
+	if msg.PrevAccessToken == "" {
+		return msg.AuthorizeCode
+	}
+	var v []byte
+	if s.get(tx, lineageIndexBucket, indexKey(msg.PrevAccessToken, accessKind, msg.AccessToken), &v) != nil {
+		return ""
+	}
+	_, code := splitLineageValue(v)
+	return code
+}
+
+// prevOrigin returns the authorize data kept by the access data refreshed
+// from the previous access token: by the previous access data, or once it
+// is gone, by the other access data refreshed from it.
+func (s *Storage) prevOrigin(tx *bolt.Tx, prev string) *model.AuthorizeData {
+	msg := &model.AccessData{}
+	if s.get(tx, accessBucket, []byte(prev), msg) == nil {
+		return msg.Origin
+	}
+	prefix := indexPrefix(prev)
+	c := tx.Bucket(lineageIndexBucket).Cursor()
+	for k, _ := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, _ = c.Next() {
+		_, token := splitIndexKey(prefix, k)
+		if s.get(tx, accessBucket, []byte(token), msg) == nil && msg.Origin != nil {
+			return msg.Origin
+		}
+	}
+	return nil
+}
+
+// linkAccess indexes access data under the access data it was refreshed
+// from. An existing entry is kept, as the previous access data may be gone.
+// Once it is, the value is taken from the entries of its other descendants.
+func (s *Storage) linkAccess(tx *bolt.Tx, msg *model.AccessData) error {
+	if msg.PrevAccessToken == "" {
+		return nil
+	}
+	k := indexKey(msg.PrevAccessToken, accessKind, msg.AccessToken)
+	if tx.Bucket(lineageIndexBucket).Get(k) != nil {
+		return nil
+	}
+	value := lineageValue("", "")
+	prev := &model.AccessData{}
+	prefix := indexPrefix(msg.PrevAccessToken)
+	if s.get(tx, accessBucket, []byte(msg.PrevAccessToken), prev) == nil {
+		value = lineageValue(prev.PrevAccessToken, s.originCode(tx, prev))
+	} else if sibling, _ := tx.Bucket(lineageIndexBucket).Cursor().Seek(prefix); sibling != nil && bytes.HasPrefix(sibling, prefix) {
+		err := s.get(tx, lineageIndexBucket, sibling, &value)
+		if err != nil {
+			return err
+		}
+	}
+	return s.put(tx, lineageIndexBucket, k, value)
+}
+
+// unlinkAccess removes the lineage entry of access data being deleted, unless
+// it has descendants, then the entries of the removed ancestors it leaves
+// without descendants.
+func (s *Storage) unlinkAccess(tx *bolt.Tx, prev string, token string) error {
+	for prev != "" && !s.hasDescendants(tx, token) {
+		k := indexKey(prev, accessKind, token)
+		var v []byte
+		if err := s.get(tx, lineageIndexBucket, k, &v); err == osin.ErrNotFound {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		if err := s.delete(tx, lineageIndexBucket, k); err != nil {
+			return err
+		}
+		if tx.Bucket(accessBucket).Get([]byte(prev)) != nil {
+			return nil
+		}
+		grand, _ := splitLineageValue(v)
+		prev, token = grand, prev
+	}
+	return nil
+}
+
+// Lineage returns the chain of access tokens the access data was refreshed
+// from and the authorization code it started from, including the removed
+// ones that are still known.
+func (s *Storage) Lineage(accessToken string) (*Lineage, error) {
+	lineage := &Lineage{}
+	err := s.db.View(func(tx *bolt.Tx) error {
+		key := s.removeKey(accessToken)
+		msg := &model.AccessData{}
+		err := s.get(tx, accessBucket, []byte(key), msg)
+		if err != nil {
+			return err
+		}
+		for prev, token := msg.PrevAccessToken, key; prev != ""; {
+			lineage.Ancestors = append(lineage.Ancestors, prev)
+			var v []byte
+			if s.get(tx, lineageIndexBucket, indexKey(prev, accessKind, token), &v) != nil {
+				break
+			}
+			grand, _ := splitLineageValue(v)
+			prev, token = grand, prev
+		}
+		lineage.AuthorizeCode = s.originCode(tx, msg)
+		if lineage.AuthorizeCode == "" {
+			return nil
+		}
+		lineage.Authorize, err = s.getAuthorize(tx, lineage.AuthorizeCode)
+		if err == osin.ErrNotFound && msg.Origin != nil && msg.Origin.Code == lineage.AuthorizeCode {
+			lineage.Authorize, err = s.authorizeData(tx, msg.Origin)
+		}
+		if err == osin.ErrNotFound {
+			err = nil
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return lineage, nil
+}
+
+// Descendants returns the keys of the access tokens refreshed, directly or
+// not, from the access token, breadth first, so everything downstream of a
+// compromised token can be revoked. The access token itself may be removed
+// already. Removed access tokens are included while they have descendants;
+// RemoveAccess ignores them.
+func (s *Storage) Descendants(accessToken string) ([]string, error) {
+	var descendants []string
+	err := s.db.View(func(tx *bolt.Tx) error {
+		descendants = s.descendants(tx, s.removeKey(accessToken))
+		return nil
+	})
+	return descendants, err
+}
+
+// descendants returns the keys of the access tokens refreshed from the access
+// token key, directly or not, breadth first.
+func (s *Storage) descendants(tx *bolt.Tx, key string) []string {
+	var descendants []string
+	c := tx.Bucket(lineageIndexBucket).Cursor()
+	queue := []string{key}
+	for len(queue) > 0 {
+		prefix := indexPrefix(queue[0])
+		queue = queue[1:]
+		for k, _ := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, _ = c.Next() {
+			_, token := splitIndexKey(prefix, k)
+			descendants = append(descendants, token)
+			queue = append(queue, token)
+		}
+	}
+	return descendants
+}
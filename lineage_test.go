@@ -0,0 +1,88 @@
+package boltdb
+
+import (
+	"testing"
+	"time"
+
+	"github.com/RangelReale/osin"
+	"github.com/boltdb/bolt"
+	"github.com/pborman/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLineage(t *testing.T) {
+	s := newStore(t)
+	client := &osin.DefaultClient{Id: "lineage", Secret: "secret", RedirectUri: "http://localhost/"}
+	createClient(t, s, client)
+
+	authorize := &osin.AuthorizeData{Client: client, Code: uuid.New(), ExpiresIn: 60, RedirectUri: "http://localhost/cb", CreatedAt: time.Now(), CodeChallenge: "challenge", CodeChallengeMethod: "S256"}
+	require.Nil(t, s.SaveAuthorize(authorize))
+	root := &osin.AccessData{Client: client, AuthorizeData: authorize, AccessToken: uuid.New(), RefreshToken: uuid.New(), ExpiresIn: 60, CreatedAt: time.Now()}
+	require.Nil(t, s.SaveAccess(root))
+	// osin removes the code once exchanged.
+	require.Nil(t, s.RemoveAuthorize(authorize.Code))
+	// Refreshing removes the previous access data, as osin does.
+	refresh := func(prev *osin.AccessData) *osin.AccessData {
+		access := &osin.AccessData{Client: client, AccessData: prev, AccessToken: uuid.New(), RefreshToken: uuid.New(), ExpiresIn: 60, CreatedAt: time.Now()}
+		require.Nil(t, s.SaveAccess(access))
+		require.Nil(t, s.RemoveAccess(prev.AccessToken))
+		return access
+	}
+	child := refresh(root)
+	grandchild := refresh(child)
+	// A sibling is saved after its parent is removed, without refreshing:
+	// the refresh token of the parent is superseded.
+	sibling := &osin.AccessData{Client: client, AccessData: &osin.AccessData{AccessToken: child.AccessToken}, AccessToken: uuid.New(), ExpiresIn: 60, CreatedAt: time.Now()}
+	require.Nil(t, s.SaveAccess(sibling))
+
+	lineage, err := s.Lineage(grandchild.AccessToken)
+	require.Nil(t, err)
+	require.Equal(t, []string{child.AccessToken, root.AccessToken}, lineage.Ancestors)
+	require.Equal(t, authorize.Code, lineage.AuthorizeCode)
+	require.NotNil(t, lineage.Authorize)
+	require.Equal(t, "http://localhost/cb", lineage.Authorize.RedirectUri)
+	require.Equal(t, "challenge", lineage.Authorize.CodeChallenge)
+	require.Equal(t, client.Id, lineage.Authorize.Client.GetId())
+
+	lineage, err = s.Lineage(sibling.AccessToken)
+	require.Nil(t, err)
+	require.Equal(t, []string{child.AccessToken, root.AccessToken}, lineage.Ancestors)
+	require.Equal(t, authorize.Code, lineage.AuthorizeCode)
+	require.NotNil(t, lineage.Authorize)
+
+	descendants, err := s.Descendants(root.AccessToken)
+	require.Nil(t, err)
+	require.Len(t, descendants, 3)
+	require.Equal(t, child.AccessToken, descendants[0])
+	require.ElementsMatch(t, []string{grandchild.AccessToken, sibling.AccessToken}, descendants[1:])
+
+	// Revoking the descendants leaves nothing indexed.
+	for _, token := range descendants {
+		require.Nil(t, s.RemoveAccess(token))
+	}
+	descendants, err = s.Descendants(root.AccessToken)
+	require.Nil(t, err)
+	require.Len(t, descendants, 0)
+	s.db.View(func(tx *bolt.Tx) error {
+		k, _ := tx.Bucket(lineageIndexBucket).Cursor().First()
+		require.Nil(t, k)
+		return nil
+	})
+
+	_, err = s.Lineage(grandchild.AccessToken)
+	require.Equal(t, osin.ErrNotFound, err)
+}
+
+func TestLineageTokenHashing(t *testing.T) {
+	s := newStore(t, WithTokenHashing(testPepper))
+	client := &osin.DefaultClient{Id: "lineage", Secret: "secret", RedirectUri: "http://localhost/"}
+	createClient(t, s, client)
+	last := saveChain(t, s, client, 3)
+
+	lineage, err := s.Lineage(last.AccessToken)
+	require.Nil(t, err)
+	require.Len(t, lineage.Ancestors, 2)
+	descendants, err := s.Descendants(lineage.Ancestors[1])
+	require.Nil(t, err)
+	require.Equal(t, []string{lineage.Ancestors[0], s.tokenKey(last.AccessToken)}, descendants)
+}
@@ -0,0 +1,192 @@
+package boltdb
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"net/url"
+	"strings"
+
+	"github.com/RangelReale/osin"
+	"github.com/boltdb/bolt"
+)
+
+// ErrInvalidCursor is returned for cursors not returned by a list method.
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+func encodeCursor(key []byte) string {
+	if key == nil {
+		return ""
+	}
+	return base64.RawURLEncoding.EncodeToString(key)
+}
+
+func decodeCursor(cursor string) ([]byte, error) {
+	if cursor == "" {
+		return nil, nil
+	}
+	key, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil || len(key) == 0 {
+		return nil, ErrInvalidCursor
+	}
+	return key, nil
+}
+
+// scanIndex calls fn with the keys of the bucket starting with prefix, minus
+// the prefix, that follow the one at cursor, until fn has accepted limit of
+// them, or for all of them if limit <= 0. It returns the cursor of the last
+// key accepted if fn accepts another key, which it is then called with add
+// false for: it must only tell whether it would accept it.
+func scanIndex(tx *bolt.Tx, bucket []byte, prefix []byte, cursor string, limit int, fn func(key []byte, add bool) (bool, error)) (string, error) {
+	after, err := decodeCursor(cursor)
+	if err != nil {
+		return "", err
+	}
+	c := tx.Bucket(bucket).Cursor()
+	k, _ := c.Seek(prefix)
+	if after != nil {
+		start := append(append([]byte(nil), prefix...), after...)
+		k, _ = c.Seek(start)
+		if bytes.Equal(k, start) {
+			k, _ = c.Next()
+		}
+	}
+	for n := 0; k != nil && bytes.HasPrefix(k, prefix); k, _ = c.Next() {
+		key := k[len(prefix):]
+		full := limit > 0 && n == limit
+		ok, err := fn(key, !full)
+		if err != nil {
+			return "", err
+		}
+		if ok && full {
+			return encodeCursor(after), nil
+		}
+		if ok {
+			n++
+			after = append([]byte(nil), key...)
+		}
+	}
+	return "", nil
+}
+
+// ListAccessByClient returns up to limit access data of the client, all of
+// them if limit <= 0, including expired ones not swept yet. The returned
+// cursor, empty after the last page, gives the next page when passed back.
+// With token hashing, the AccessToken and RefreshToken of the access data
+// are the hashed keys they are stored under, not the tokens: RemoveAccess
+// and RemoveRefresh accept them, LoadAccess and LoadRefresh do not.
+func (s *Storage) ListAccessByClient(clientID string, cursor string, limit int) ([]*osin.AccessData, string, error) {
+	tx, err := s.db.Begin(false)
+	if err != nil {
+		return nil, "", err
+	}
+	defer tx.Rollback()
+
+	var list []*osin.AccessData
+	prefix := indexKey(clientID, accessKind, "")
+	next, err := scanIndex(tx, clientIndexBucket, prefix, cursor, limit, func(key []byte, add bool) (bool, error) {
+		if !add {
+			return tx.Bucket(accessBucket).Get(key) != nil, nil
+		}
+		access, err := s.getAccess(tx, string(key))
+		if err == osin.ErrNotFound {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		list = append(list, access)
+		return true, nil
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	return list, next, nil
+}
+
+// CountAccessByClient returns the number of access data of the client,
+// including expired ones not swept yet.
+func (s *Storage) CountAccessByClient(clientID string) (int, error) {
+	n := 0
+	err := s.db.View(func(tx *bolt.Tx) error {
+		prefix := indexKey(clientID, accessKind, "")
+		c := tx.Bucket(clientIndexBucket).Cursor()
+		for k, _ := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, _ = c.Next() {
+			n++
+		}
+		return nil
+	})
+	return n, err
+}
+
+// ClientFilter selects the clients returned by ListClients. Its zero fields
+// select every client.
+type ClientFilter struct {
+	// IdPrefix selects the clients whose id starts with it.
+	IdPrefix string
+	// RedirectHost selects the clients with a redirect URI, or a redirect
+	// URI in their metadata, that has this host.
+	RedirectHost string
+	// RedirectUriSeparator splits the redirect URI of the clients into
+	// several, as the RedirectUriSeparator of the osin.ServerConfig does.
+	RedirectUriSeparator string
+	// UserData selects the clients whose UserData it returns true for.
+	UserData func(userData interface{}) bool
+}
+
+func (f *ClientFilter) match(client osin.Client) bool {
+	if f.RedirectHost != "" && !f.redirectHostMatches(client) {
+		return false
+	}
+	return f.UserData == nil || f.UserData(client.GetUserData())
+}
+
+func (f *ClientFilter) redirectHostMatches(client osin.Client) bool {
+	uris := []string{client.GetRedirectUri()}
+	if f.RedirectUriSeparator != "" {
+		uris = strings.Split(uris[0], f.RedirectUriSeparator)
+	}
+	if metadata := MetadataOf(client); metadata != nil {
+		uris = append(uris, metadata.RedirectUris...)
+	}
+	for _, uri := range uris {
+		u, err := url.Parse(uri)
+		if err == nil && strings.EqualFold(u.Hostname(), f.RedirectHost) {
+			return true
+		}
+	}
+	return false
+}
+
+// ListClients returns up to limit clients in id order, all of them if
+// limit <= 0, selected by filter if not nil. The returned cursor, empty after
+// the last page, gives the next page when passed back with the same filter.
+func (s *Storage) ListClients(cursor string, limit int, filter *ClientFilter) ([]osin.Client, string, error) {
+	if filter == nil {
+		filter = &ClientFilter{}
+	}
+	tx, err := s.db.Begin(false)
+	if err != nil {
+		return nil, "", err
+	}
+	defer tx.Rollback()
+
+	var list []osin.Client
+	next, err := scanIndex(tx, clientBucket, []byte(filter.IdPrefix), cursor, limit, func(key []byte, add bool) (bool, error) {
+		client, err := s.getClient(tx, filter.IdPrefix+string(key))
+		if err != nil {
+			return false, err
+		}
+		if !filter.match(client) {
+			return false, nil
+		}
+		if add {
+			list = append(list, client)
+		}
+		return true, nil
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	return list, next, nil
+}
@@ -0,0 +1,150 @@
+package boltdb
+
+import (
+	"testing"
+	"time"
+
+	"github.com/RangelReale/osin"
+	"github.com/pborman/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListAccessByClient(t *testing.T) {
+	s := newStore(t)
+	client := &osin.DefaultClient{Id: "listed", Secret: "secret", RedirectUri: "http://localhost/"}
+	other := &osin.DefaultClient{Id: "listed2", Secret: "secret", RedirectUri: "http://localhost/"}
+	createClient(t, s, client)
+	createClient(t, s, other)
+
+	tokens := make(map[string]bool)
+	for i := 0; i < 5; i++ {
+		access := &osin.AccessData{Client: client, AccessToken: uuid.New(), RefreshToken: uuid.New(), ExpiresIn: 60, CreatedAt: time.Now()}
+		require.Nil(t, s.SaveAccess(access))
+		tokens[access.AccessToken] = true
+	}
+	require.Nil(t, s.SaveAuthorize(&osin.AuthorizeData{Client: client, Code: uuid.New(), ExpiresIn: 60, CreatedAt: time.Now()}))
+	require.Nil(t, s.SaveAccess(&osin.AccessData{Client: other, AccessToken: uuid.New(), ExpiresIn: 60, CreatedAt: time.Now()}))
+
+	n, err := s.CountAccessByClient(client.Id)
+	require.Nil(t, err)
+	require.Equal(t, 5, n)
+
+	seen := make(map[string]bool)
+	cursor, pages := "", 0
+	for {
+		list, next, err := s.ListAccessByClient(client.Id, cursor, 2)
+		require.Nil(t, err)
+		require.True(t, len(list) <= 2)
+		for _, access := range list {
+			require.Equal(t, client.Id, access.Client.GetId())
+			seen[access.AccessToken] = true
+		}
+		pages++
+		if next == "" {
+			break
+		}
+		cursor = next
+	}
+	require.Equal(t, 3, pages)
+	require.Equal(t, tokens, seen)
+
+	for _, limit := range []int{0, 5} {
+		list, next, err := s.ListAccessByClient(client.Id, "", limit)
+		require.Nil(t, err)
+		require.Len(t, list, 5)
+		require.Equal(t, "", next)
+	}
+
+	_, _, err = s.ListAccessByClient(client.Id, "!", 2)
+	require.Equal(t, ErrInvalidCursor, err)
+
+	n, err = s.CountAccessByClient("unknown")
+	require.Nil(t, err)
+	require.Equal(t, 0, n)
+}
+
+func TestListAccessByClientTokenHashing(t *testing.T) {
+	s := newStore(t, WithTokenHashing(testPepper))
+	client := &osin.DefaultClient{Id: "listed", Secret: "secret", RedirectUri: "http://localhost/"}
+	createClient(t, s, client)
+	access := &osin.AccessData{Client: client, AccessToken: uuid.New(), RefreshToken: uuid.New(), ExpiresIn: 60, CreatedAt: time.Now()}
+	require.Nil(t, s.SaveAccess(access))
+
+	list, _, err := s.ListAccessByClient(client.Id, "", 0)
+	require.Nil(t, err)
+	require.Len(t, list, 1)
+	require.Equal(t, s.tokenKey(access.AccessToken), list[0].AccessToken)
+	require.Equal(t, s.tokenKey(access.RefreshToken), list[0].RefreshToken)
+
+	// The keys revoke the tokens.
+	require.Nil(t, s.RemoveRefresh(list[0].RefreshToken))
+	require.Nil(t, s.RemoveAccess(list[0].AccessToken))
+	_, err = s.LoadAccess(access.AccessToken)
+	require.Equal(t, osin.ErrNotFound, err)
+	_, err = s.LoadRefresh(access.RefreshToken)
+	require.Equal(t, osin.ErrNotFound, err)
+}
+
+func TestListClients(t *testing.T) {
+	s := newStore(t)
+	for _, client := range []*osin.DefaultClient{
+		{Id: "app-1", RedirectUri: "http://one.example.com/callback", UserData: "internal"},
+		{Id: "app-2", RedirectUri: "http://two.example.com/callback"},
+		{Id: "app-3", RedirectUri: "http://one.example.com/", UserData: "internal"},
+		{Id: "tool-1", RedirectUri: "http://one.example.com/"},
+		{Id: "tool-2", RedirectUri: "http://two.example.com/", UserData: "internal"},
+	} {
+		createClient(t, s, client)
+	}
+
+	list := func(limit int, filter *ClientFilter) (ids []string, pages int) {
+		cursor := ""
+		for {
+			clients, next, err := s.ListClients(cursor, limit, filter)
+			require.Nil(t, err)
+			for _, client := range clients {
+				ids = append(ids, client.GetId())
+			}
+			pages++
+			if next == "" {
+				return
+			}
+			cursor = next
+		}
+	}
+
+	ids, pages := list(2, nil)
+	require.Equal(t, []string{"app-1", "app-2", "app-3", "tool-1", "tool-2"}, ids)
+	require.Equal(t, 3, pages)
+
+	ids, _ = list(1, &ClientFilter{IdPrefix: "app-"})
+	require.Equal(t, []string{"app-1", "app-2", "app-3"}, ids)
+
+	ids, _ = list(0, &ClientFilter{RedirectHost: "ONE.example.com"})
+	require.Equal(t, []string{"app-1", "app-3", "tool-1"}, ids)
+
+	internal := func(userData interface{}) bool {
+		return userData == "internal"
+	}
+	ids, pages = list(2, &ClientFilter{IdPrefix: "app-", RedirectHost: "one.example.com", UserData: internal})
+	require.Equal(t, []string{"app-1", "app-3"}, ids)
+	require.Equal(t, 1, pages)
+	// The last page is not followed by an empty one.
+	ids, pages = list(1, &ClientFilter{RedirectHost: "two.example.com"})
+	require.Equal(t, []string{"app-2", "tool-2"}, ids)
+	require.Equal(t, 2, pages)
+
+	ids, _ = list(0, &ClientFilter{IdPrefix: "none"})
+	require.Nil(t, ids)
+
+	// Every redirect URI of a client is considered.
+	createClient(t, s, &osin.DefaultClient{Id: "multi", RedirectUri: "http://two.example.com/|http://three.example.com/"})
+	createClient(t, s, &RegisteredClient{
+		Client:   &osin.DefaultClient{Id: "registered", RedirectUri: "http://two.example.com/"},
+		Metadata: ClientMetadata{RedirectUris: []string{"http://two.example.com/", "http://three.example.com/"}},
+	})
+	ids, _ = list(0, &ClientFilter{RedirectHost: "three.example.com"})
+	require.Equal(t, []string{"registered"}, ids)
+	ids, _ = list(0, &ClientFilter{RedirectHost: "three.example.com", RedirectUriSeparator: "|"})
+	require.Equal(t, []string{"multi", "registered"}, ids)
+}
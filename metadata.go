@@ -0,0 +1,316 @@
+package boltdb
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"net/url"
+	"strings"
+
+	"github.com/RangelReale/osin"
+
+	"github.com/dcalandria/osin-boltdb/model"
+)
+
+// Error codes of RFC 7591, as recorded in ClientMetadataError.
+const (
+	ErrorInvalidRedirectURI    = "invalid_redirect_uri"
+	ErrorInvalidClientMetadata = "invalid_client_metadata"
+)
+
+// Token endpoint authentication methods of RFC 7591.
+const (
+	AuthMethodNone              = "none"
+	AuthMethodClientSecretPost  = "client_secret_post"
+	AuthMethodClientSecretBasic = "client_secret_basic"
+	AuthMethodClientSecretJWT   = "client_secret_jwt"
+	AuthMethodPrivateKeyJWT     = "private_key_jwt"
+)
+
+// ClientMetadata is the RFC 7591 metadata of a client. Its JSON encoding is
+// the one of RFC 7591.
+type ClientMetadata struct {
+	RedirectUris            []string        `json:"redirect_uris,omitempty"`
+	TokenEndpointAuthMethod string          `json:"token_endpoint_auth_method,omitempty"`
+	GrantTypes              []string        `json:"grant_types,omitempty"`
+	ResponseTypes           []string        `json:"response_types,omitempty"`
+	ClientName              string          `json:"client_name,omitempty"`
+	ClientUri               string          `json:"client_uri,omitempty"`
+	LogoUri                 string          `json:"logo_uri,omitempty"`
+	Scope                   string          `json:"scope,omitempty"`
+	Contacts                []string        `json:"contacts,omitempty"`
+	TosUri                  string          `json:"tos_uri,omitempty"`
+	PolicyUri               string          `json:"policy_uri,omitempty"`
+	JwksUri                 string          `json:"jwks_uri,omitempty"`
+	Jwks                    json.RawMessage `json:"jwks,omitempty"`
+	SoftwareId              string          `json:"software_id,omitempty"`
+	SoftwareVersion         string          `json:"software_version,omitempty"`
+}
+
+// ClientMetadataError reports client metadata CreateClient or UpdateClient
+// rejected.
+type ClientMetadataError struct {
+	// Code is ErrorInvalidRedirectURI or ErrorInvalidClientMetadata.
+	Code        string
+	Description string
+}
+
+func (e *ClientMetadataError) Error() string {
+	return e.Code + ": " + e.Description
+}
+
+func invalidMetadata(description string) error {
+	return &ClientMetadataError{Code: ErrorInvalidClientMetadata, Description: description}
+}
+
+// responseGrants maps response types to the grant type they belong to.
+var responseGrants = map[string]string{
+	"code":  "authorization_code",
+	"token": "implicit",
+}
+
+// grantTypes returns the grant types of the client. Unset, they are those of
+// its response types, or authorization_code.
+func (m *ClientMetadata) grantTypes() []string {
+	if len(m.GrantTypes) > 0 {
+		return m.GrantTypes
+	}
+	var grantTypes []string
+	for _, responseType := range m.ResponseTypes {
+		if grantType, ok := responseGrants[responseType]; ok {
+			grantTypes = append(grantTypes, grantType)
+		}
+	}
+	if len(grantTypes) == 0 {
+		return []string{"authorization_code"}
+	}
+	return grantTypes
+}
+
+// responseTypes returns the response types of the client. Unset, they are
+// those of its grant types.
+func (m *ClientMetadata) responseTypes() []string {
+	if len(m.ResponseTypes) > 0 {
+		return m.ResponseTypes
+	}
+	var responseTypes []string
+	for _, grantType := range m.grantTypes() {
+		for responseType, g := range responseGrants {
+			if g == grantType {
+				responseTypes = append(responseTypes, responseType)
+			}
+		}
+	}
+	return responseTypes
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// AllowsGrantType reports whether the client may use grantType, such as
+// authorization_code or refresh_token. The implicit grant is also known as
+// osin's __implicit. A nil ClientMetadata, the one of clients without
+// metadata, allows any grant type.
+func (m *ClientMetadata) AllowsGrantType(grantType string) bool {
+	if m == nil {
+		return true
+	}
+	if grantType == "__implicit" {
+		grantType = "implicit"
+	}
+	return contains(m.grantTypes(), grantType)
+}
+
+// AllowsResponseType reports whether the client may use responseType, code or
+// token. A nil ClientMetadata allows any response type.
+func (m *ClientMetadata) AllowsResponseType(responseType string) bool {
+	return m == nil || contains(m.responseTypes(), responseType)
+}
+
+// AllowsScope reports whether the client may request each of the space
+// separated scopes of scope. A nil ClientMetadata, or one without Scope,
+// allows any scope.
+func (m *ClientMetadata) AllowsScope(scope string) bool {
+	if m == nil || m.Scope == "" {
+		return true
+	}
+	allowed := strings.Fields(m.Scope)
+	for _, s := range strings.Fields(scope) {
+		if !contains(allowed, s) {
+			return false
+		}
+	}
+	return true
+}
+
+func validURI(uri string) bool {
+	u, err := url.Parse(uri)
+	return err == nil && u.IsAbs() && u.Host != ""
+}
+
+// Validate checks the metadata as RFC 7591 requires: absolute redirect URIs
+// without fragment, response types consistent with the grant types, a known
+// token endpoint authentication method, absolute URLs and at most one of
+// Jwks and JwksUri, Jwks being a JWK Set.
+func (m *ClientMetadata) Validate() error {
+	for _, uri := range m.RedirectUris {
+		u, err := url.Parse(uri)
+		if err != nil || !u.IsAbs() || u.Fragment != "" {
+			return &ClientMetadataError{Code: ErrorInvalidRedirectURI, Description: "invalid redirect URI " + uri}
+		}
+	}
+
+	grantTypes, responseTypes := m.grantTypes(), m.responseTypes()
+	for _, responseType := range responseTypes {
+		grantType, ok := responseGrants[responseType]
+		if !ok {
+			return invalidMetadata("unsupported response type " + responseType)
+		}
+		if !contains(grantTypes, grantType) {
+			return invalidMetadata("response type " + responseType + " requires grant type " + grantType)
+		}
+	}
+	for _, grantType := range grantTypes {
+		if grantType == "" {
+			return invalidMetadata("empty grant type")
+		}
+		for responseType, g := range responseGrants {
+			if g == grantType && !contains(responseTypes, responseType) {
+				return invalidMetadata("grant type " + grantType + " requires response type " + responseType)
+			}
+		}
+	}
+	if (contains(grantTypes, "authorization_code") || contains(grantTypes, "implicit")) && len(m.RedirectUris) == 0 {
+		return &ClientMetadataError{Code: ErrorInvalidRedirectURI, Description: "redirect URIs required"}
+	}
+
+	switch m.TokenEndpointAuthMethod {
+	case "", AuthMethodNone, AuthMethodClientSecretPost, AuthMethodClientSecretBasic,
+		AuthMethodClientSecretJWT, AuthMethodPrivateKeyJWT:
+	default:
+		return invalidMetadata("unsupported token endpoint auth method " + m.TokenEndpointAuthMethod)
+	}
+
+	for _, uri := range []struct{ name, value string }{
+		{"client_uri", m.ClientUri},
+		{"logo_uri", m.LogoUri},
+		{"tos_uri", m.TosUri},
+		{"policy_uri", m.PolicyUri},
+		{"jwks_uri", m.JwksUri},
+	} {
+		if uri.value != "" && !validURI(uri.value) {
+			return invalidMetadata("invalid " + uri.name)
+		}
+	}
+	for _, contact := range m.Contacts {
+		if strings.TrimSpace(contact) == "" {
+			return invalidMetadata("empty contact")
+		}
+	}
+
+	if len(m.Jwks) > 0 {
+		if m.JwksUri != "" {
+			return invalidMetadata("jwks and jwks_uri are mutually exclusive")
+		}
+		var set struct {
+			Keys []json.RawMessage `json:"keys"`
+		}
+		if json.Unmarshal(m.Jwks, &set) != nil || set.Keys == nil {
+			return invalidMetadata("jwks is not a JWK Set")
+		}
+	}
+	return nil
+}
+
+func (m *ClientMetadata) model() *model.ClientMetadata {
+	return &model.ClientMetadata{
+		RedirectUris:            m.RedirectUris,
+		TokenEndpointAuthMethod: m.TokenEndpointAuthMethod,
+		GrantTypes:              m.GrantTypes,
+		ResponseTypes:           m.ResponseTypes,
+		ClientName:              m.ClientName,
+		ClientUri:               m.ClientUri,
+		LogoUri:                 m.LogoUri,
+		Scope:                   m.Scope,
+		Contacts:                m.Contacts,
+		TosUri:                  m.TosUri,
+		PolicyUri:               m.PolicyUri,
+		JwksUri:                 m.JwksUri,
+		Jwks:                    m.Jwks,
+		SoftwareId:              m.SoftwareId,
+		SoftwareVersion:         m.SoftwareVersion,
+	}
+}
+
+func clientMetadata(msg *model.ClientMetadata) ClientMetadata {
+	m := ClientMetadata{
+		RedirectUris:            msg.RedirectUris,
+		TokenEndpointAuthMethod: msg.TokenEndpointAuthMethod,
+		GrantTypes:              msg.GrantTypes,
+		ResponseTypes:           msg.ResponseTypes,
+		ClientName:              msg.ClientName,
+		ClientUri:               msg.ClientUri,
+		LogoUri:                 msg.LogoUri,
+		Scope:                   msg.Scope,
+		Contacts:                msg.Contacts,
+		TosUri:                  msg.TosUri,
+		PolicyUri:               msg.PolicyUri,
+		JwksUri:                 msg.JwksUri,
+		SoftwareId:              msg.SoftwareId,
+		SoftwareVersion:         msg.SoftwareVersion,
+	}
+	if len(msg.Jwks) > 0 {
+		m.Jwks = msg.Jwks
+	}
+	return m
+}
+
+// RegisteredClient is an osin.Client with RFC 7591 metadata. GetClient returns
+// one for the clients stored with metadata, wrapping the client it returns
+// otherwise.
+//
+// osin checks redirect URIs against GetRedirectUri, that of the wrapped
+// client. For it to accept each of RedirectUris, set it to them joined by the
+// RedirectUriSeparator of the osin.ServerConfig.
+type RegisteredClient struct {
+	osin.Client
+	Metadata ClientMetadata
+}
+
+func (c *RegisteredClient) Unwrap() osin.Client {
+	return c.Client
+}
+
+// ClientSecretMatches implements osin.ClientSecretMatcher, with the wrapped
+// client when it does.
+func (c *RegisteredClient) ClientSecretMatches(secret string) bool {
+	if matcher, ok := c.Client.(osin.ClientSecretMatcher); ok {
+		return matcher.ClientSecretMatches(secret)
+	}
+	return subtle.ConstantTimeCompare([]byte(c.GetSecret()), []byte(secret)) == 1
+}
+
+var _ osin.ClientSecretMatcher = (*RegisteredClient)(nil)
+
+// MetadataOf returns the metadata of client, a *RegisteredClient or a client
+// wrapping one, or nil for clients without metadata. The result, nil
+// included, tells which grant types, response types and scopes the client
+// is allowed.
+func MetadataOf(client osin.Client) *ClientMetadata {
+	for client != nil {
+		if c, ok := client.(*RegisteredClient); ok {
+			return &c.Metadata
+		}
+		wrapper, ok := client.(interface{ Unwrap() osin.Client })
+		if !ok {
+			return nil
+		}
+		client = wrapper.Unwrap()
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+package boltdb
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/RangelReale/osin"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientMetadata(t *testing.T) {
+	s := newStore(t)
+	registered := &RegisteredClient{
+		Client: &osin.DefaultClient{Id: "registered", Secret: "secret", RedirectUri: "https://app/cb|https://app/cb2"},
+		Metadata: ClientMetadata{
+			RedirectUris:            []string{"https://app/cb", "https://app/cb2"},
+			TokenEndpointAuthMethod: AuthMethodClientSecretBasic,
+			GrantTypes:              []string{"authorization_code", "refresh_token"},
+			ResponseTypes:           []string{"code"},
+			ClientName:              "App",
+			ClientUri:               "https://app/",
+			LogoUri:                 "https://app/logo.png",
+			Scope:                   "read write",
+			Contacts:                []string{"admin@app"},
+			TosUri:                  "https://app/tos",
+			PolicyUri:               "https://app/policy",
+			Jwks:                    json.RawMessage(`{"keys":[{"kty":"EC"}]}`),
+			SoftwareId:              "app",
+			SoftwareVersion:         "1.0",
+		},
+	}
+	createClient(t, s, registered)
+	client, err := s.GetClient("registered")
+	require.Nil(t, err)
+	require.Equal(t, registered, client)
+	require.True(t, client.(osin.ClientSecretMatcher).ClientSecretMatches("secret"))
+	require.False(t, client.(osin.ClientSecretMatcher).ClientSecretMatches("wrong"))
+
+	metadata := MetadataOf(client)
+	require.True(t, metadata.AllowsGrantType("authorization_code"))
+	require.True(t, metadata.AllowsGrantType("refresh_token"))
+	require.False(t, metadata.AllowsGrantType("password"))
+	require.True(t, metadata.AllowsResponseType("code"))
+	require.False(t, metadata.AllowsResponseType("token"))
+	require.True(t, metadata.AllowsScope("read"))
+	require.True(t, metadata.AllowsScope("write read"))
+	require.False(t, metadata.AllowsScope("read admin"))
+
+	// Updating without metadata drops it, and clients without metadata are
+	// unrestricted.
+	require.Nil(t, s.UpdateClient(registered.Client))
+	client, err = s.GetClient("registered")
+	require.Nil(t, err)
+	require.Equal(t, registered.Client, client)
+	metadata = MetadataOf(client)
+	require.Nil(t, metadata)
+	require.True(t, metadata.AllowsGrantType("password"))
+	require.True(t, metadata.AllowsResponseType("token"))
+	require.True(t, metadata.AllowsScope("admin"))
+
+	err = s.UpdateClient(&RegisteredClient{Client: registered.Client, Metadata: ClientMetadata{}})
+	require.IsType(t, &ClientMetadataError{}, err)
+	require.Equal(t, ErrorInvalidRedirectURI, err.(*ClientMetadataError).Code)
+}
+
+func TestClientMetadataDefaults(t *testing.T) {
+	metadata := &ClientMetadata{RedirectUris: []string{"https://app/cb"}}
+	require.Nil(t, metadata.Validate())
+	require.True(t, metadata.AllowsGrantType("authorization_code"))
+	require.False(t, metadata.AllowsGrantType("refresh_token"))
+	require.True(t, metadata.AllowsResponseType("code"))
+
+	metadata = &ClientMetadata{RedirectUris: []string{"https://app/cb"}, ResponseTypes: []string{"token"}}
+	require.Nil(t, metadata.Validate())
+	require.True(t, metadata.AllowsGrantType("__implicit"))
+	require.False(t, metadata.AllowsGrantType("authorization_code"))
+
+	metadata = &ClientMetadata{GrantTypes: []string{"client_credentials"}}
+	require.Nil(t, metadata.Validate())
+	require.False(t, metadata.AllowsResponseType("code"))
+}
+
+func TestClientMetadataValidation(t *testing.T) {
+	redirect := []string{"https://app/cb"}
+	for _, c := range []struct {
+		metadata ClientMetadata
+		code     string
+	}{
+		{ClientMetadata{}, ErrorInvalidRedirectURI},
+		{ClientMetadata{RedirectUris: []string{"/cb"}}, ErrorInvalidRedirectURI},
+		{ClientMetadata{RedirectUris: []string{"https://app/cb#fragment"}}, ErrorInvalidRedirectURI},
+		{ClientMetadata{RedirectUris: redirect, ResponseTypes: []string{"id_token"}}, ErrorInvalidClientMetadata},
+		{ClientMetadata{RedirectUris: redirect, GrantTypes: []string{"implicit"}, ResponseTypes: []string{"code"}}, ErrorInvalidClientMetadata},
+		{ClientMetadata{RedirectUris: redirect, GrantTypes: []string{"authorization_code", "implicit"}, ResponseTypes: []string{"code"}}, ErrorInvalidClientMetadata},
+		{ClientMetadata{GrantTypes: []string{""}}, ErrorInvalidClientMetadata},
+		{ClientMetadata{RedirectUris: redirect, TokenEndpointAuthMethod: "magic"}, ErrorInvalidClientMetadata},
+		{ClientMetadata{RedirectUris: redirect, LogoUri: "logo.png"}, ErrorInvalidClientMetadata},
+		{ClientMetadata{RedirectUris: redirect, Contacts: []string{" "}}, ErrorInvalidClientMetadata},
+		{ClientMetadata{RedirectUris: redirect, Jwks: json.RawMessage(`{"keys":[]}`), JwksUri: "https://app/jwks"}, ErrorInvalidClientMetadata},
+		{ClientMetadata{RedirectUris: redirect, Jwks: json.RawMessage(`{}`)}, ErrorInvalidClientMetadata},
+		{ClientMetadata{RedirectUris: redirect, Jwks: json.RawMessage(`[`)}, ErrorInvalidClientMetadata},
+	} {
+		err := c.metadata.Validate()
+		require.IsType(t, &ClientMetadataError{}, err, "%+v", c.metadata)
+		require.Equal(t, c.code, err.(*ClientMetadataError).Code, "%+v", c.metadata)
+	}
+}
+
+func TestClientMetadataSecretHashing(t *testing.T) {
+	s := newStore(t, WithSecretHashing(PBKDF2Hasher(1000)))
+	createClient(t, s, &RegisteredClient{
+		Client:   &osin.DefaultClient{Id: "registered", Secret: "secret", RedirectUri: "https://app/cb"},
+		Metadata: ClientMetadata{RedirectUris: []string{"https://app/cb"}},
+	})
+	requireNotStored(t, s, "secret")
+
+	client, err := s.GetClient("registered")
+	require.Nil(t, err)
+	require.IsType(t, &HashedClient{}, client.(*RegisteredClient).Client)
+
+	// Updating a loaded client keeps its secret.
+	client.(*RegisteredClient).Metadata.ClientName = "App"
+	require.Nil(t, s.UpdateClient(client))
+	client, err = s.GetClient("registered")
+	require.Nil(t, err)
+	require.Equal(t, "App", MetadataOf(client).ClientName)
+	require.True(t, client.(osin.ClientSecretMatcher).ClientSecretMatches("secret"))
+}
@@ -0,0 +1,101 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// JSONCodec is a UserDataCodec encoding as JSON the values DefaultUserDataCodec
+// does not support, such as structs. The values of registered types are
+// stored with the name they were registered with, and decoded to their type.
+// The others are decoded like encoding/json does into an interface{}, objects
+// to map[string]interface{}.
+type JSONCodec struct {
+	mu    sync.RWMutex
+	types map[string]reflect.Type
+	names map[reflect.Type]string
+}
+
+// NewJSONCodec returns a JSONCodec without registered types.
+func NewJSONCodec() *JSONCodec {
+	return &JSONCodec{
+		types: make(map[string]reflect.Type),
+		names: make(map[reflect.Type]string),
+	}
+}
+
+// Register records the type of v under name. Registering a name or a type
+// twice panics.
+func (c *JSONCodec) Register(name string, v interface{}) {
+	t := reflect.TypeOf(v)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.types[name]; ok {
+		panic(fmt.Sprintf("model: registering duplicate name %q", name))
+	}
+	if _, ok := c.names[t]; ok {
+		panic(fmt.Sprintf("model: registering duplicate type %s", t))
+	}
+	c.types[name] = t
+	c.names[t] = name
+}
+
+func (c *JSONCodec) EncodeUserData(v interface{}) (*UserData, error) {
+	var name string
+	if v != nil {
+		c.mu.RLock()
+		name = c.names[reflect.TypeOf(v)]
+		c.mu.RUnlock()
+	}
+	if name == "" {
+		userData, err := defaultCodec{}.EncodeUserData(v)
+		if err != ErrUnsupportedType {
+			return userData, err
+		}
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return &UserData{
+		Type: UserData_JSON,
+		Name: name,
+		Data: data,
+	}, nil
+}
+
+func (c *JSONCodec) DecodeUserData(userData *UserData) (interface{}, error) {
+	if userData.Type != UserData_JSON || userData.Name == "" {
+		return defaultCodec{}.DecodeUserData(userData)
+	}
+
+	c.mu.RLock()
+	t, ok := c.types[userData.Name]
+	c.mu.RUnlock()
+	if !ok {
+		return decodeJSON(userData.Data)
+	}
+	if t.Kind() == reflect.Ptr {
+		v := reflect.New(t.Elem())
+		if err := json.Unmarshal(userData.Data, v.Interface()); err != nil {
+			return nil, err
+		}
+		return v.Interface(), nil
+	}
+	v := reflect.New(t)
+	if err := json.Unmarshal(userData.Data, v.Interface()); err != nil {
+		return nil, err
+	}
+	return v.Elem().Interface(), nil
+}
+
+func decodeJSON(data []byte) (interface{}, error) {
+	var v interface{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
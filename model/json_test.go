@@ -0,0 +1,62 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type profile struct {
+	Name  string   `json:"name"`
+	Roles []string `json:"roles"`
+}
+
+func TestJSONCodec(t *testing.T) {
+	c := NewJSONCodec()
+	c.Register("profile", profile{})
+	c.Register("profile-ptr", &profile{})
+	require.Panics(t, func() { c.Register("profile", 1) })
+
+	for _, v := range []interface{}{
+		nil,
+		"alice",
+		int64(42),
+		true,
+		profile{Name: "alice", Roles: []string{"admin"}},
+		&profile{Name: "bob"},
+	} {
+		userData, err := c.EncodeUserData(v)
+		require.Nil(t, err)
+		decoded, err := c.DecodeUserData(userData)
+		require.Nil(t, err)
+		require.Equal(t, v, decoded)
+	}
+
+	userData, err := c.EncodeUserData(profile{Name: "alice"})
+	require.Nil(t, err)
+	require.Equal(t, UserData_JSON, userData.Type)
+	require.Equal(t, "profile", userData.Name)
+
+	// Unregistered types decode to their generic JSON form.
+	userData, err = c.EncodeUserData(struct {
+		Name string `json:"name"`
+	}{"carol"})
+	require.Nil(t, err)
+	require.Equal(t, UserData_JSON, userData.Type)
+	require.Equal(t, "", userData.Name)
+	decoded, err := c.DecodeUserData(userData)
+	require.Nil(t, err)
+	require.Equal(t, map[string]interface{}{"name": "carol"}, decoded)
+
+	// So do the types of another codec, including for the default codec.
+	userData, err = c.EncodeUserData(profile{Name: "alice"})
+	require.Nil(t, err)
+	for _, other := range []UserDataCodec{NewJSONCodec(), DefaultUserDataCodec} {
+		decoded, err = other.DecodeUserData(userData)
+		require.Nil(t, err)
+		require.Equal(t, map[string]interface{}{"name": "alice", "roles": nil}, decoded)
+	}
+
+	_, err = c.DecodeUserData(&UserData{Type: UserData_JSON, Name: "profile", Data: []byte("{")})
+	require.NotNil(t, err)
+}
@@ -9,9 +9,14 @@
 
 	It has these top-level messages:
 		UserData
+		SecretHash
 		Client
+		RefreshPolicy
 		AuthorizeData
 		AccessData
+		RefreshFamily
+		ClientMetadata
+		ClientSecret
 */
 package model
 
@@ -35,35 +40,50 @@ const _ = proto.GoGoProtoPackageIsVersion2 // please upgrade the proto package
 type UserData_Type int32
 
 const (
-	UserData_NIL    UserData_Type = 0
-	UserData_PROTO  UserData_Type = 1
-	UserData_BYTES  UserData_Type = 2
-	UserData_STRING UserData_Type = 3
-	UserData_INT    UserData_Type = 4
-	UserData_UINT   UserData_Type = 5
-	UserData_BOOL   UserData_Type = 6
-	UserData_FLOAT  UserData_Type = 7
+	UserData_NIL      UserData_Type = 0
+	UserData_PROTO    UserData_Type = 1
+	UserData_BYTES    UserData_Type = 2
+	UserData_STRING   UserData_Type = 3
+	UserData_INT      UserData_Type = 4
+	UserData_UINT     UserData_Type = 5
+	UserData_BOOL     UserData_Type = 6
+	UserData_FLOAT    UserData_Type = 7
+	UserData_JSON     UserData_Type = 8
+	UserData_MAP      UserData_Type = 9
+	UserData_LIST     UserData_Type = 10
+	UserData_TIME     UserData_Type = 11
+	UserData_DURATION UserData_Type = 12
 )
 
 var UserData_Type_name = map[int32]string{
-	0: "NIL",
-	1: "PROTO",
-	2: "BYTES",
-	3: "STRING",
-	4: "INT",
-	5: "UINT",
-	6: "BOOL",
-	7: "FLOAT",
+	0:  "NIL",
+	1:  "PROTO",
+	2:  "BYTES",
+	3:  "STRING",
+	4:  "INT",
+	5:  "UINT",
+	6:  "BOOL",
+	7:  "FLOAT",
+	8:  "JSON",
+	9:  "MAP",
+	10: "LIST",
+	11: "TIME",
+	12: "DURATION",
 }
 var UserData_Type_value = map[string]int32{
-	"NIL":    0,
-	"PROTO":  1,
-	"BYTES":  2,
-	"STRING": 3,
-	"INT":    4,
-	"UINT":   5,
-	"BOOL":   6,
-	"FLOAT":  7,
+	"NIL":      0,
+	"PROTO":    1,
+	"BYTES":    2,
+	"STRING":   3,
+	"INT":      4,
+	"UINT":     5,
+	"BOOL":     6,
+	"FLOAT":    7,
+	"JSON":     8,
+	"MAP":      9,
+	"LIST":     10,
+	"TIME":     11,
+	"DURATION": 12,
 }
 
 func (x UserData_Type) String() string {
@@ -103,17 +123,80 @@ func (m *UserData) GetData() []byte {
 	return nil
 }
 
+type SecretHash struct {
+	Algorithm  string `protobuf:"bytes,1,opt,name=algorithm,proto3" json:"algorithm,omitempty"`
+	Salt       []byte `protobuf:"bytes,2,opt,name=salt,proto3" json:"salt,omitempty"`
+	Hash       []byte `protobuf:"bytes,3,opt,name=hash,proto3" json:"hash,omitempty"`
+	Iterations uint32 `protobuf:"varint,4,opt,name=iterations,proto3" json:"iterations,omitempty"`
+	Memory     uint32 `protobuf:"varint,5,opt,name=memory,proto3" json:"memory,omitempty"`
+	Threads    uint32 `protobuf:"varint,6,opt,name=threads,proto3" json:"threads,omitempty"`
+}
+
+func (m *SecretHash) Reset()                    { *m = SecretHash{} }
+func (m *SecretHash) String() string            { return proto.CompactTextString(m) }
+func (*SecretHash) ProtoMessage()               {}
+func (*SecretHash) Descriptor() ([]byte, []int) { return fileDescriptorModel, []int{1} }
+
+func (m *SecretHash) GetAlgorithm() string {
+	if m != nil {
+		return m.Algorithm
+	}
+	return ""
+}
+
+func (m *SecretHash) GetSalt() []byte {
+	if m != nil {
+		return m.Salt
+	}
+	return nil
+}
+
+func (m *SecretHash) GetHash() []byte {
+	if m != nil {
+		return m.Hash
+	}
+	return nil
+}
+
+func (m *SecretHash) GetIterations() uint32 {
+	if m != nil {
+		return m.Iterations
+	}
+	return 0
+}
+
+func (m *SecretHash) GetMemory() uint32 {
+	if m != nil {
+		return m.Memory
+	}
+	return 0
+}
+
+func (m *SecretHash) GetThreads() uint32 {
+	if m != nil {
+		return m.Threads
+	}
+	return 0
+}
+
 type Client struct {
-	Id          string    `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Secret      string    `protobuf:"bytes,2,opt,name=secret,proto3" json:"secret,omitempty"`
-	RedirectUri string    `protobuf:"bytes,3,opt,name=redirect_uri,json=redirectUri,proto3" json:"redirect_uri,omitempty"`
-	UserData    *UserData `protobuf:"bytes,4,opt,name=user_data,json=userData" json:"user_data,omitempty"`
+	Id                string            `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Secret            string            `protobuf:"bytes,2,opt,name=secret,proto3" json:"secret,omitempty"`
+	RedirectUri       string            `protobuf:"bytes,3,opt,name=redirect_uri,json=redirectUri,proto3" json:"redirect_uri,omitempty"`
+	UserData          *UserData         `protobuf:"bytes,4,opt,name=user_data,json=userData" json:"user_data,omitempty"`
+	SecretHash        *SecretHash       `protobuf:"bytes,5,opt,name=secret_hash,json=secretHash" json:"secret_hash,omitempty"`
+	RefreshPolicy     *RefreshPolicy    `protobuf:"bytes,6,opt,name=refresh_policy,json=refreshPolicy" json:"refresh_policy,omitempty"`
+	Metadata          map[string][]byte `protobuf:"bytes,7,rep,name=metadata" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	ClientMetadata    *ClientMetadata   `protobuf:"bytes,8,opt,name=client_metadata,json=clientMetadata" json:"client_metadata,omitempty"`
+	RegistrationToken string            `protobuf:"bytes,9,opt,name=registration_token,json=registrationToken,proto3" json:"registration_token,omitempty"`
+	Secrets           []*ClientSecret   `protobuf:"bytes,10,rep,name=secrets" json:"secrets,omitempty"`
+	CreatedAt         []byte            `protobuf:"bytes,11,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
 }
 
 func (m *Client) Reset()                    { *m = Client{} }
 func (m *Client) String() string            { return proto.CompactTextString(m) }
 func (*Client) ProtoMessage()               {}
-func (*Client) Descriptor() ([]byte, []int) { return fileDescriptorModel, []int{1} }
+func (*Client) Descriptor() ([]byte, []int) { return fileDescriptorModel, []int{2} }
 
 func (m *Client) GetId() string {
 	if m != nil {
@@ -143,6 +226,79 @@ func (m *Client) GetUserData() *UserData {
 	return nil
 }
 
+func (m *Client) GetSecretHash() *SecretHash {
+	if m != nil {
+		return m.SecretHash
+	}
+	return nil
+}
+
+func (m *Client) GetRefreshPolicy() *RefreshPolicy {
+	if m != nil {
+		return m.RefreshPolicy
+	}
+	return nil
+}
+
+func (m *Client) GetMetadata() map[string][]byte {
+	if m != nil {
+		return m.Metadata
+	}
+	return nil
+}
+
+func (m *Client) GetClientMetadata() *ClientMetadata {
+	if m != nil {
+		return m.ClientMetadata
+	}
+	return nil
+}
+
+func (m *Client) GetRegistrationToken() string {
+	if m != nil {
+		return m.RegistrationToken
+	}
+	return ""
+}
+
+func (m *Client) GetSecrets() []*ClientSecret {
+	if m != nil {
+		return m.Secrets
+	}
+	return nil
+}
+
+func (m *Client) GetCreatedAt() []byte {
+	if m != nil {
+		return m.CreatedAt
+	}
+	return nil
+}
+
+type RefreshPolicy struct {
+	MaxLifetime int64 `protobuf:"varint,1,opt,name=max_lifetime,json=maxLifetime,proto3" json:"max_lifetime,omitempty"`
+	IdleTimeout int64 `protobuf:"varint,2,opt,name=idle_timeout,json=idleTimeout,proto3" json:"idle_timeout,omitempty"`
+}
+
+func (m *RefreshPolicy) Reset()                    { *m = RefreshPolicy{} }
+func (m *RefreshPolicy) String() string            { return proto.CompactTextString(m) }
+func (*RefreshPolicy) ProtoMessage()               {}
+func (*RefreshPolicy) Descriptor() ([]byte, []int) { return fileDescriptorModel, []int{3} }
+
+func (m *RefreshPolicy) GetMaxLifetime() int64 {
+	if m != nil {
+		return m.MaxLifetime
+	}
+	return 0
+}
+
+func (m *RefreshPolicy) GetIdleTimeout() int64 {
+	if m != nil {
+		return m.IdleTimeout
+	}
+	return 0
+}
+
 type AuthorizeData struct {
 	ClientId            string    `protobuf:"bytes,1,opt,name=client_id,json=clientId,proto3" json:"client_id,omitempty"`
 	Code                string    `protobuf:"bytes,2,opt,name=code,proto3" json:"code,omitempty"`
@@ -154,12 +310,14 @@ type AuthorizeData struct {
 	UserData            *UserData `protobuf:"bytes,8,opt,name=user_data,json=userData" json:"user_data,omitempty"`
 	CodeChallenge       string    `protobuf:"bytes,9,opt,name=code_challenge,json=codeChallenge,proto3" json:"code_challenge,omitempty"`
 	CodeChallengeMethod string    `protobuf:"bytes,10,opt,name=code_challenge_method,json=codeChallengeMethod,proto3" json:"code_challenge_method,omitempty"`
+	Used                bool      `protobuf:"varint,11,opt,name=used,proto3" json:"used,omitempty"`
+	AccessTokens        []string  `protobuf:"bytes,12,rep,name=access_tokens,json=accessTokens" json:"access_tokens,omitempty"`
 }
 
 func (m *AuthorizeData) Reset()                    { *m = AuthorizeData{} }
 func (m *AuthorizeData) String() string            { return proto.CompactTextString(m) }
 func (*AuthorizeData) ProtoMessage()               {}
-func (*AuthorizeData) Descriptor() ([]byte, []int) { return fileDescriptorModel, []int{2} }
+func (*AuthorizeData) Descriptor() ([]byte, []int) { return fileDescriptorModel, []int{4} }
 
 func (m *AuthorizeData) GetClientId() string {
 	if m != nil {
@@ -231,23 +389,40 @@ func (m *AuthorizeData) GetCodeChallengeMethod() string {
 	return ""
 }
 
+func (m *AuthorizeData) GetUsed() bool {
+	if m != nil {
+		return m.Used
+	}
+	return false
+}
+
+func (m *AuthorizeData) GetAccessTokens() []string {
+	if m != nil {
+		return m.AccessTokens
+	}
+	return nil
+}
+
 type AccessData struct {
-	ClientId        string    `protobuf:"bytes,1,opt,name=client_id,json=clientId,proto3" json:"client_id,omitempty"`
-	AuthorizeCode   string    `protobuf:"bytes,2,opt,name=authorize_code,json=authorizeCode,proto3" json:"authorize_code,omitempty"`
-	PrevAccessToken string    `protobuf:"bytes,3,opt,name=prev_access_token,json=prevAccessToken,proto3" json:"prev_access_token,omitempty"`
-	AccessToken     string    `protobuf:"bytes,4,opt,name=access_token,json=accessToken,proto3" json:"access_token,omitempty"`
-	RefreshToken    string    `protobuf:"bytes,5,opt,name=refresh_token,json=refreshToken,proto3" json:"refresh_token,omitempty"`
-	ExpiresIn       int32     `protobuf:"varint,6,opt,name=expires_in,json=expiresIn,proto3" json:"expires_in,omitempty"`
-	Scope           string    `protobuf:"bytes,7,opt,name=scope,proto3" json:"scope,omitempty"`
-	RedirectUri     string    `protobuf:"bytes,8,opt,name=redirect_uri,json=redirectUri,proto3" json:"redirect_uri,omitempty"`
-	CreatedAt       []byte    `protobuf:"bytes,9,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
-	UserData        *UserData `protobuf:"bytes,10,opt,name=user_data,json=userData" json:"user_data,omitempty"`
+	ClientId        string         `protobuf:"bytes,1,opt,name=client_id,json=clientId,proto3" json:"client_id,omitempty"`
+	AuthorizeCode   string         `protobuf:"bytes,2,opt,name=authorize_code,json=authorizeCode,proto3" json:"authorize_code,omitempty"`
+	PrevAccessToken string         `protobuf:"bytes,3,opt,name=prev_access_token,json=prevAccessToken,proto3" json:"prev_access_token,omitempty"`
+	AccessToken     string         `protobuf:"bytes,4,opt,name=access_token,json=accessToken,proto3" json:"access_token,omitempty"`
+	RefreshToken    string         `protobuf:"bytes,5,opt,name=refresh_token,json=refreshToken,proto3" json:"refresh_token,omitempty"`
+	ExpiresIn       int32          `protobuf:"varint,6,opt,name=expires_in,json=expiresIn,proto3" json:"expires_in,omitempty"`
+	Scope           string         `protobuf:"bytes,7,opt,name=scope,proto3" json:"scope,omitempty"`
+	RedirectUri     string         `protobuf:"bytes,8,opt,name=redirect_uri,json=redirectUri,proto3" json:"redirect_uri,omitempty"`
+	CreatedAt       []byte         `protobuf:"bytes,9,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UserData        *UserData      `protobuf:"bytes,10,opt,name=user_data,json=userData" json:"user_data,omitempty"`
+	FamilyId        string         `protobuf:"bytes,11,opt,name=family_id,json=familyId,proto3" json:"family_id,omitempty"`
+	FamilyCreatedAt []byte         `protobuf:"bytes,12,opt,name=family_created_at,json=familyCreatedAt,proto3" json:"family_created_at,omitempty"`
+	Origin          *AuthorizeData `protobuf:"bytes,13,opt,name=origin" json:"origin,omitempty"`
 }
 
 func (m *AccessData) Reset()                    { *m = AccessData{} }
 func (m *AccessData) String() string            { return proto.CompactTextString(m) }
 func (*AccessData) ProtoMessage()               {}
-func (*AccessData) Descriptor() ([]byte, []int) { return fileDescriptorModel, []int{3} }
+func (*AccessData) Descriptor() ([]byte, []int) { return fileDescriptorModel, []int{5} }
 
 func (m *AccessData) GetClientId() string {
 	if m != nil {
@@ -319,11 +494,245 @@ func (m *AccessData) GetUserData() *UserData {
 	return nil
 }
 
+func (m *AccessData) GetFamilyId() string {
+	if m != nil {
+		return m.FamilyId
+	}
+	return ""
+}
+
+func (m *AccessData) GetFamilyCreatedAt() []byte {
+	if m != nil {
+		return m.FamilyCreatedAt
+	}
+	return nil
+}
+
+func (m *AccessData) GetOrigin() *AuthorizeData {
+	if m != nil {
+		return m.Origin
+	}
+	return nil
+}
+
+type RefreshFamily struct {
+	Id           string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	ClientId     string `protobuf:"bytes,2,opt,name=client_id,json=clientId,proto3" json:"client_id,omitempty"`
+	RefreshToken string `protobuf:"bytes,3,opt,name=refresh_token,json=refreshToken,proto3" json:"refresh_token,omitempty"`
+}
+
+func (m *RefreshFamily) Reset()                    { *m = RefreshFamily{} }
+func (m *RefreshFamily) String() string            { return proto.CompactTextString(m) }
+func (*RefreshFamily) ProtoMessage()               {}
+func (*RefreshFamily) Descriptor() ([]byte, []int) { return fileDescriptorModel, []int{6} }
+
+func (m *RefreshFamily) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *RefreshFamily) GetClientId() string {
+	if m != nil {
+		return m.ClientId
+	}
+	return ""
+}
+
+func (m *RefreshFamily) GetRefreshToken() string {
+	if m != nil {
+		return m.RefreshToken
+	}
+	return ""
+}
+
+type ClientMetadata struct {
+	RedirectUris            []string `protobuf:"bytes,1,rep,name=redirect_uris,json=redirectUris" json:"redirect_uris,omitempty"`
+	TokenEndpointAuthMethod string   `protobuf:"bytes,2,opt,name=token_endpoint_auth_method,json=tokenEndpointAuthMethod,proto3" json:"token_endpoint_auth_method,omitempty"`
+	GrantTypes              []string `protobuf:"bytes,3,rep,name=grant_types,json=grantTypes" json:"grant_types,omitempty"`
+	ResponseTypes           []string `protobuf:"bytes,4,rep,name=response_types,json=responseTypes" json:"response_types,omitempty"`
+	ClientName              string   `protobuf:"bytes,5,opt,name=client_name,json=clientName,proto3" json:"client_name,omitempty"`
+	ClientUri               string   `protobuf:"bytes,6,opt,name=client_uri,json=clientUri,proto3" json:"client_uri,omitempty"`
+	LogoUri                 string   `protobuf:"bytes,7,opt,name=logo_uri,json=logoUri,proto3" json:"logo_uri,omitempty"`
+	Scope                   string   `protobuf:"bytes,8,opt,name=scope,proto3" json:"scope,omitempty"`
+	Contacts                []string `protobuf:"bytes,9,rep,name=contacts" json:"contacts,omitempty"`
+	TosUri                  string   `protobuf:"bytes,10,opt,name=tos_uri,json=tosUri,proto3" json:"tos_uri,omitempty"`
+	PolicyUri               string   `protobuf:"bytes,11,opt,name=policy_uri,json=policyUri,proto3" json:"policy_uri,omitempty"`
+	JwksUri                 string   `protobuf:"bytes,12,opt,name=jwks_uri,json=jwksUri,proto3" json:"jwks_uri,omitempty"`
+	Jwks                    []byte   `protobuf:"bytes,13,opt,name=jwks,proto3" json:"jwks,omitempty"`
+	SoftwareId              string   `protobuf:"bytes,14,opt,name=software_id,json=softwareId,proto3" json:"software_id,omitempty"`
+	SoftwareVersion         string   `protobuf:"bytes,15,opt,name=software_version,json=softwareVersion,proto3" json:"software_version,omitempty"`
+}
+
+func (m *ClientMetadata) Reset()                    { *m = ClientMetadata{} }
+func (m *ClientMetadata) String() string            { return proto.CompactTextString(m) }
+func (*ClientMetadata) ProtoMessage()               {}
+func (*ClientMetadata) Descriptor() ([]byte, []int) { return fileDescriptorModel, []int{7} }
+
+func (m *ClientMetadata) GetRedirectUris() []string {
+	if m != nil {
+		return m.RedirectUris
+	}
+	return nil
+}
+
+func (m *ClientMetadata) GetTokenEndpointAuthMethod() string {
+	if m != nil {
+		return m.TokenEndpointAuthMethod
+	}
+	return ""
+}
+
+func (m *ClientMetadata) GetGrantTypes() []string {
+	if m != nil {
+		return m.GrantTypes
+	}
+	return nil
+}
+
+func (m *ClientMetadata) GetResponseTypes() []string {
+	if m != nil {
+		return m.ResponseTypes
+	}
+	return nil
+}
+
+func (m *ClientMetadata) GetClientName() string {
+	if m != nil {
+		return m.ClientName
+	}
+	return ""
+}
+
+func (m *ClientMetadata) GetClientUri() string {
+	if m != nil {
+		return m.ClientUri
+	}
+	return ""
+}
+
+func (m *ClientMetadata) GetLogoUri() string {
+	if m != nil {
+		return m.LogoUri
+	}
+	return ""
+}
+
+func (m *ClientMetadata) GetScope() string {
+	if m != nil {
+		return m.Scope
+	}
+	return ""
+}
+
+func (m *ClientMetadata) GetContacts() []string {
+	if m != nil {
+		return m.Contacts
+	}
+	return nil
+}
+
+func (m *ClientMetadata) GetTosUri() string {
+	if m != nil {
+		return m.TosUri
+	}
+	return ""
+}
+
+func (m *ClientMetadata) GetPolicyUri() string {
+	if m != nil {
+		return m.PolicyUri
+	}
+	return ""
+}
+
+func (m *ClientMetadata) GetJwksUri() string {
+	if m != nil {
+		return m.JwksUri
+	}
+	return ""
+}
+
+func (m *ClientMetadata) GetJwks() []byte {
+	if m != nil {
+		return m.Jwks
+	}
+	return nil
+}
+
+func (m *ClientMetadata) GetSoftwareId() string {
+	if m != nil {
+		return m.SoftwareId
+	}
+	return ""
+}
+
+func (m *ClientMetadata) GetSoftwareVersion() string {
+	if m != nil {
+		return m.SoftwareVersion
+	}
+	return ""
+}
+
+type ClientSecret struct {
+	Id         string      `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Secret     string      `protobuf:"bytes,2,opt,name=secret,proto3" json:"secret,omitempty"`
+	SecretHash *SecretHash `protobuf:"bytes,3,opt,name=secret_hash,json=secretHash" json:"secret_hash,omitempty"`
+	CreatedAt  []byte      `protobuf:"bytes,4,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	ExpiresAt  []byte      `protobuf:"bytes,5,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+}
+
+func (m *ClientSecret) Reset()                    { *m = ClientSecret{} }
+func (m *ClientSecret) String() string            { return proto.CompactTextString(m) }
+func (*ClientSecret) ProtoMessage()               {}
+func (*ClientSecret) Descriptor() ([]byte, []int) { return fileDescriptorModel, []int{8} }
+
+func (m *ClientSecret) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *ClientSecret) GetSecret() string {
+	if m != nil {
+		return m.Secret
+	}
+	return ""
+}
+
+func (m *ClientSecret) GetSecretHash() *SecretHash {
+	if m != nil {
+		return m.SecretHash
+	}
+	return nil
+}
+
+func (m *ClientSecret) GetCreatedAt() []byte {
+	if m != nil {
+		return m.CreatedAt
+	}
+	return nil
+}
+
+func (m *ClientSecret) GetExpiresAt() []byte {
+	if m != nil {
+		return m.ExpiresAt
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*UserData)(nil), "model.UserData")
+	proto.RegisterType((*SecretHash)(nil), "model.SecretHash")
 	proto.RegisterType((*Client)(nil), "model.Client")
+	proto.RegisterType((*RefreshPolicy)(nil), "model.RefreshPolicy")
 	proto.RegisterType((*AuthorizeData)(nil), "model.AuthorizeData")
 	proto.RegisterType((*AccessData)(nil), "model.AccessData")
+	proto.RegisterType((*RefreshFamily)(nil), "model.RefreshFamily")
+	proto.RegisterType((*ClientMetadata)(nil), "model.ClientMetadata")
+	proto.RegisterType((*ClientSecret)(nil), "model.ClientSecret")
 	proto.RegisterEnum("model.UserData_Type", UserData_Type_name, UserData_Type_value)
 }
 func (m *UserData) Marshal() (dAtA []byte, err error) {
@@ -361,7 +770,7 @@ func (m *UserData) MarshalTo(dAtA []byte) (int, error) {
 	return i, nil
 }
 
-func (m *Client) Marshal() (dAtA []byte, err error) {
+func (m *SecretHash) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalTo(dAtA)
@@ -371,38 +780,194 @@ func (m *Client) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *Client) MarshalTo(dAtA []byte) (int, error) {
+func (m *SecretHash) MarshalTo(dAtA []byte) (int, error) {
 	var i int
 	_ = i
 	var l int
 	_ = l
-	if len(m.Id) > 0 {
+	if len(m.Algorithm) > 0 {
 		dAtA[i] = 0xa
 		i++
-		i = encodeVarintModel(dAtA, i, uint64(len(m.Id)))
-		i += copy(dAtA[i:], m.Id)
+		i = encodeVarintModel(dAtA, i, uint64(len(m.Algorithm)))
+		i += copy(dAtA[i:], m.Algorithm)
 	}
-	if len(m.Secret) > 0 {
+	if len(m.Salt) > 0 {
 		dAtA[i] = 0x12
 		i++
-		i = encodeVarintModel(dAtA, i, uint64(len(m.Secret)))
-		i += copy(dAtA[i:], m.Secret)
+		i = encodeVarintModel(dAtA, i, uint64(len(m.Salt)))
+		i += copy(dAtA[i:], m.Salt)
 	}
-	if len(m.RedirectUri) > 0 {
+	if len(m.Hash) > 0 {
 		dAtA[i] = 0x1a
 		i++
-		i = encodeVarintModel(dAtA, i, uint64(len(m.RedirectUri)))
-		i += copy(dAtA[i:], m.RedirectUri)
+		i = encodeVarintModel(dAtA, i, uint64(len(m.Hash)))
+		i += copy(dAtA[i:], m.Hash)
 	}
-	if m.UserData != nil {
-		dAtA[i] = 0x22
+	if m.Iterations != 0 {
+		dAtA[i] = 0x20
 		i++
-		i = encodeVarintModel(dAtA, i, uint64(m.UserData.Size()))
-		n1, err := m.UserData.MarshalTo(dAtA[i:])
-		if err != nil {
-			return 0, err
-		}
-		i += n1
+		i = encodeVarintModel(dAtA, i, uint64(m.Iterations))
+	}
+	if m.Memory != 0 {
+		dAtA[i] = 0x28
+		i++
+		i = encodeVarintModel(dAtA, i, uint64(m.Memory))
+	}
+	if m.Threads != 0 {
+		dAtA[i] = 0x30
+		i++
+		i = encodeVarintModel(dAtA, i, uint64(m.Threads))
+	}
+	return i, nil
+}
+
+func (m *Client) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Client) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Id) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintModel(dAtA, i, uint64(len(m.Id)))
+		i += copy(dAtA[i:], m.Id)
+	}
+	if len(m.Secret) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintModel(dAtA, i, uint64(len(m.Secret)))
+		i += copy(dAtA[i:], m.Secret)
+	}
+	if len(m.RedirectUri) > 0 {
+		dAtA[i] = 0x1a
+		i++
+		i = encodeVarintModel(dAtA, i, uint64(len(m.RedirectUri)))
+		i += copy(dAtA[i:], m.RedirectUri)
+	}
+	if m.UserData != nil {
+		dAtA[i] = 0x22
+		i++
+		i = encodeVarintModel(dAtA, i, uint64(m.UserData.Size()))
+		n1, err := m.UserData.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n1
+	}
+	if m.SecretHash != nil {
+		dAtA[i] = 0x2a
+		i++
+		i = encodeVarintModel(dAtA, i, uint64(m.SecretHash.Size()))
+		n2, err := m.SecretHash.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n2
+	}
+	if m.RefreshPolicy != nil {
+		dAtA[i] = 0x32
+		i++
+		i = encodeVarintModel(dAtA, i, uint64(m.RefreshPolicy.Size()))
+		n3, err := m.RefreshPolicy.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n3
+	}
+	if len(m.Metadata) > 0 {
+		for k, _ := range m.Metadata {
+			dAtA[i] = 0x3a
+			i++
+			v := m.Metadata[k]
+			byteSize := 0
+			if len(v) > 0 {
+				byteSize = 1 + len(v) + sovModel(uint64(len(v)))
+			}
+			mapSize := 1 + len(k) + sovModel(uint64(len(k))) + byteSize
+			i = encodeVarintModel(dAtA, i, uint64(mapSize))
+			dAtA[i] = 0xa
+			i++
+			i = encodeVarintModel(dAtA, i, uint64(len(k)))
+			i += copy(dAtA[i:], k)
+			if len(v) > 0 {
+				dAtA[i] = 0x12
+				i++
+				i = encodeVarintModel(dAtA, i, uint64(len(v)))
+				i += copy(dAtA[i:], v)
+			}
+		}
+	}
+	if m.ClientMetadata != nil {
+		dAtA[i] = 0x42
+		i++
+		i = encodeVarintModel(dAtA, i, uint64(m.ClientMetadata.Size()))
+		n4, err := m.ClientMetadata.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n4
+	}
+	if len(m.RegistrationToken) > 0 {
+		dAtA[i] = 0x4a
+		i++
+		i = encodeVarintModel(dAtA, i, uint64(len(m.RegistrationToken)))
+		i += copy(dAtA[i:], m.RegistrationToken)
+	}
+	if len(m.Secrets) > 0 {
+		for _, msg := range m.Secrets {
+			dAtA[i] = 0x52
+			i++
+			i = encodeVarintModel(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	if len(m.CreatedAt) > 0 {
+		dAtA[i] = 0x5a
+		i++
+		i = encodeVarintModel(dAtA, i, uint64(len(m.CreatedAt)))
+		i += copy(dAtA[i:], m.CreatedAt)
+	}
+	return i, nil
+}
+
+func (m *RefreshPolicy) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *RefreshPolicy) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.MaxLifetime != 0 {
+		dAtA[i] = 0x8
+		i++
+		i = encodeVarintModel(dAtA, i, uint64(m.MaxLifetime))
+	}
+	if m.IdleTimeout != 0 {
+		dAtA[i] = 0x10
+		i++
+		i = encodeVarintModel(dAtA, i, uint64(m.IdleTimeout))
 	}
 	return i, nil
 }
@@ -467,11 +1032,11 @@ func (m *AuthorizeData) MarshalTo(dAtA []byte) (int, error) {
 		dAtA[i] = 0x42
 		i++
 		i = encodeVarintModel(dAtA, i, uint64(m.UserData.Size()))
-		n2, err := m.UserData.MarshalTo(dAtA[i:])
+		n5, err := m.UserData.MarshalTo(dAtA[i:])
 		if err != nil {
 			return 0, err
 		}
-		i += n2
+		i += n5
 	}
 	if len(m.CodeChallenge) > 0 {
 		dAtA[i] = 0x4a
@@ -485,6 +1050,31 @@ func (m *AuthorizeData) MarshalTo(dAtA []byte) (int, error) {
 		i = encodeVarintModel(dAtA, i, uint64(len(m.CodeChallengeMethod)))
 		i += copy(dAtA[i:], m.CodeChallengeMethod)
 	}
+	if m.Used {
+		dAtA[i] = 0x58
+		i++
+		if m.Used {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i++
+	}
+	if len(m.AccessTokens) > 0 {
+		for _, s := range m.AccessTokens {
+			dAtA[i] = 0x62
+			i++
+			l = len(s)
+			for l >= 1<<7 {
+				dAtA[i] = uint8(uint64(l)&0x7f | 0x80)
+				l >>= 7
+				i++
+			}
+			dAtA[i] = uint8(l)
+			i++
+			i += copy(dAtA[i:], s)
+		}
+	}
 	return i, nil
 }
 
@@ -560,112 +1150,452 @@ func (m *AccessData) MarshalTo(dAtA []byte) (int, error) {
 		dAtA[i] = 0x52
 		i++
 		i = encodeVarintModel(dAtA, i, uint64(m.UserData.Size()))
-		n3, err := m.UserData.MarshalTo(dAtA[i:])
+		n6, err := m.UserData.MarshalTo(dAtA[i:])
 		if err != nil {
 			return 0, err
 		}
-		i += n3
+		i += n6
+	}
+	if len(m.FamilyId) > 0 {
+		dAtA[i] = 0x5a
+		i++
+		i = encodeVarintModel(dAtA, i, uint64(len(m.FamilyId)))
+		i += copy(dAtA[i:], m.FamilyId)
+	}
+	if len(m.FamilyCreatedAt) > 0 {
+		dAtA[i] = 0x62
+		i++
+		i = encodeVarintModel(dAtA, i, uint64(len(m.FamilyCreatedAt)))
+		i += copy(dAtA[i:], m.FamilyCreatedAt)
+	}
+	if m.Origin != nil {
+		dAtA[i] = 0x6a
+		i++
+		i = encodeVarintModel(dAtA, i, uint64(m.Origin.Size()))
+		n7, err := m.Origin.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n7
 	}
 	return i, nil
 }
 
-func encodeVarintModel(dAtA []byte, offset int, v uint64) int {
-	for v >= 1<<7 {
-		dAtA[offset] = uint8(v&0x7f | 0x80)
-		v >>= 7
-		offset++
+func (m *RefreshFamily) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
 	}
-	dAtA[offset] = uint8(v)
-	return offset + 1
+	return dAtA[:n], nil
 }
-func (m *UserData) Size() (n int) {
+
+func (m *RefreshFamily) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
 	var l int
 	_ = l
-	if m.Type != 0 {
-		n += 1 + sovModel(uint64(m.Type))
+	if len(m.Id) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintModel(dAtA, i, uint64(len(m.Id)))
+		i += copy(dAtA[i:], m.Id)
 	}
-	l = len(m.Name)
-	if l > 0 {
-		n += 1 + l + sovModel(uint64(l))
+	if len(m.ClientId) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintModel(dAtA, i, uint64(len(m.ClientId)))
+		i += copy(dAtA[i:], m.ClientId)
 	}
-	l = len(m.Data)
-	if l > 0 {
-		n += 1 + l + sovModel(uint64(l))
+	if len(m.RefreshToken) > 0 {
+		dAtA[i] = 0x1a
+		i++
+		i = encodeVarintModel(dAtA, i, uint64(len(m.RefreshToken)))
+		i += copy(dAtA[i:], m.RefreshToken)
 	}
-	return n
+	return i, nil
 }
 
-func (m *Client) Size() (n int) {
+func (m *ClientMetadata) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ClientMetadata) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
 	var l int
 	_ = l
-	l = len(m.Id)
-	if l > 0 {
-		n += 1 + l + sovModel(uint64(l))
+	if len(m.RedirectUris) > 0 {
+		for _, s := range m.RedirectUris {
+			dAtA[i] = 0xa
+			i++
+			l = len(s)
+			for l >= 1<<7 {
+				dAtA[i] = uint8(uint64(l)&0x7f | 0x80)
+				l >>= 7
+				i++
+			}
+			dAtA[i] = uint8(l)
+			i++
+			i += copy(dAtA[i:], s)
+		}
 	}
-	l = len(m.Secret)
-	if l > 0 {
-		n += 1 + l + sovModel(uint64(l))
+	if len(m.TokenEndpointAuthMethod) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintModel(dAtA, i, uint64(len(m.TokenEndpointAuthMethod)))
+		i += copy(dAtA[i:], m.TokenEndpointAuthMethod)
 	}
-	l = len(m.RedirectUri)
-	if l > 0 {
-		n += 1 + l + sovModel(uint64(l))
+	if len(m.GrantTypes) > 0 {
+		for _, s := range m.GrantTypes {
+			dAtA[i] = 0x1a
+			i++
+			l = len(s)
+			for l >= 1<<7 {
+				dAtA[i] = uint8(uint64(l)&0x7f | 0x80)
+				l >>= 7
+				i++
+			}
+			dAtA[i] = uint8(l)
+			i++
+			i += copy(dAtA[i:], s)
+		}
 	}
-	if m.UserData != nil {
-		l = m.UserData.Size()
-		n += 1 + l + sovModel(uint64(l))
+	if len(m.ResponseTypes) > 0 {
+		for _, s := range m.ResponseTypes {
+			dAtA[i] = 0x22
+			i++
+			l = len(s)
+			for l >= 1<<7 {
+				dAtA[i] = uint8(uint64(l)&0x7f | 0x80)
+				l >>= 7
+				i++
+			}
+			dAtA[i] = uint8(l)
+			i++
+			i += copy(dAtA[i:], s)
+		}
 	}
-	return n
-}
-
-func (m *AuthorizeData) Size() (n int) {
-	var l int
-	_ = l
-	l = len(m.ClientId)
-	if l > 0 {
-		n += 1 + l + sovModel(uint64(l))
+	if len(m.ClientName) > 0 {
+		dAtA[i] = 0x2a
+		i++
+		i = encodeVarintModel(dAtA, i, uint64(len(m.ClientName)))
+		i += copy(dAtA[i:], m.ClientName)
 	}
-	l = len(m.Code)
-	if l > 0 {
-		n += 1 + l + sovModel(uint64(l))
+	if len(m.ClientUri) > 0 {
+		dAtA[i] = 0x32
+		i++
+		i = encodeVarintModel(dAtA, i, uint64(len(m.ClientUri)))
+		i += copy(dAtA[i:], m.ClientUri)
 	}
-	if m.ExpiresIn != 0 {
-		n += 1 + sovModel(uint64(m.ExpiresIn))
+	if len(m.LogoUri) > 0 {
+		dAtA[i] = 0x3a
+		i++
+		i = encodeVarintModel(dAtA, i, uint64(len(m.LogoUri)))
+		i += copy(dAtA[i:], m.LogoUri)
 	}
-	l = len(m.Scope)
-	if l > 0 {
-		n += 1 + l + sovModel(uint64(l))
+	if len(m.Scope) > 0 {
+		dAtA[i] = 0x42
+		i++
+		i = encodeVarintModel(dAtA, i, uint64(len(m.Scope)))
+		i += copy(dAtA[i:], m.Scope)
 	}
-	l = len(m.RedirectUri)
-	if l > 0 {
-		n += 1 + l + sovModel(uint64(l))
+	if len(m.Contacts) > 0 {
+		for _, s := range m.Contacts {
+			dAtA[i] = 0x4a
+			i++
+			l = len(s)
+			for l >= 1<<7 {
+				dAtA[i] = uint8(uint64(l)&0x7f | 0x80)
+				l >>= 7
+				i++
+			}
+			dAtA[i] = uint8(l)
+			i++
+			i += copy(dAtA[i:], s)
+		}
 	}
-	l = len(m.State)
-	if l > 0 {
-		n += 1 + l + sovModel(uint64(l))
+	if len(m.TosUri) > 0 {
+		dAtA[i] = 0x52
+		i++
+		i = encodeVarintModel(dAtA, i, uint64(len(m.TosUri)))
+		i += copy(dAtA[i:], m.TosUri)
 	}
-	l = len(m.CreatedAt)
-	if l > 0 {
-		n += 1 + l + sovModel(uint64(l))
+	if len(m.PolicyUri) > 0 {
+		dAtA[i] = 0x5a
+		i++
+		i = encodeVarintModel(dAtA, i, uint64(len(m.PolicyUri)))
+		i += copy(dAtA[i:], m.PolicyUri)
 	}
-	if m.UserData != nil {
-		l = m.UserData.Size()
-		n += 1 + l + sovModel(uint64(l))
+	if len(m.JwksUri) > 0 {
+		dAtA[i] = 0x62
+		i++
+		i = encodeVarintModel(dAtA, i, uint64(len(m.JwksUri)))
+		i += copy(dAtA[i:], m.JwksUri)
 	}
-	l = len(m.CodeChallenge)
-	if l > 0 {
-		n += 1 + l + sovModel(uint64(l))
+	if len(m.Jwks) > 0 {
+		dAtA[i] = 0x6a
+		i++
+		i = encodeVarintModel(dAtA, i, uint64(len(m.Jwks)))
+		i += copy(dAtA[i:], m.Jwks)
 	}
-	l = len(m.CodeChallengeMethod)
-	if l > 0 {
-		n += 1 + l + sovModel(uint64(l))
+	if len(m.SoftwareId) > 0 {
+		dAtA[i] = 0x72
+		i++
+		i = encodeVarintModel(dAtA, i, uint64(len(m.SoftwareId)))
+		i += copy(dAtA[i:], m.SoftwareId)
 	}
-	return n
+	if len(m.SoftwareVersion) > 0 {
+		dAtA[i] = 0x7a
+		i++
+		i = encodeVarintModel(dAtA, i, uint64(len(m.SoftwareVersion)))
+		i += copy(dAtA[i:], m.SoftwareVersion)
+	}
+	return i, nil
 }
 
-func (m *AccessData) Size() (n int) {
-	var l int
-	_ = l
-	l = len(m.ClientId)
+func (m *ClientSecret) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ClientSecret) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Id) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintModel(dAtA, i, uint64(len(m.Id)))
+		i += copy(dAtA[i:], m.Id)
+	}
+	if len(m.Secret) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintModel(dAtA, i, uint64(len(m.Secret)))
+		i += copy(dAtA[i:], m.Secret)
+	}
+	if m.SecretHash != nil {
+		dAtA[i] = 0x1a
+		i++
+		i = encodeVarintModel(dAtA, i, uint64(m.SecretHash.Size()))
+		n8, err := m.SecretHash.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n8
+	}
+	if len(m.CreatedAt) > 0 {
+		dAtA[i] = 0x22
+		i++
+		i = encodeVarintModel(dAtA, i, uint64(len(m.CreatedAt)))
+		i += copy(dAtA[i:], m.CreatedAt)
+	}
+	if len(m.ExpiresAt) > 0 {
+		dAtA[i] = 0x2a
+		i++
+		i = encodeVarintModel(dAtA, i, uint64(len(m.ExpiresAt)))
+		i += copy(dAtA[i:], m.ExpiresAt)
+	}
+	return i, nil
+}
+
+func encodeVarintModel(dAtA []byte, offset int, v uint64) int {
+	for v >= 1<<7 {
+		dAtA[offset] = uint8(v&0x7f | 0x80)
+		v >>= 7
+		offset++
+	}
+	dAtA[offset] = uint8(v)
+	return offset + 1
+}
+func (m *UserData) Size() (n int) {
+	var l int
+	_ = l
+	if m.Type != 0 {
+		n += 1 + sovModel(uint64(m.Type))
+	}
+	l = len(m.Name)
+	if l > 0 {
+		n += 1 + l + sovModel(uint64(l))
+	}
+	l = len(m.Data)
+	if l > 0 {
+		n += 1 + l + sovModel(uint64(l))
+	}
+	return n
+}
+
+func (m *SecretHash) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.Algorithm)
+	if l > 0 {
+		n += 1 + l + sovModel(uint64(l))
+	}
+	l = len(m.Salt)
+	if l > 0 {
+		n += 1 + l + sovModel(uint64(l))
+	}
+	l = len(m.Hash)
+	if l > 0 {
+		n += 1 + l + sovModel(uint64(l))
+	}
+	if m.Iterations != 0 {
+		n += 1 + sovModel(uint64(m.Iterations))
+	}
+	if m.Memory != 0 {
+		n += 1 + sovModel(uint64(m.Memory))
+	}
+	if m.Threads != 0 {
+		n += 1 + sovModel(uint64(m.Threads))
+	}
+	return n
+}
+
+func (m *Client) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.Id)
+	if l > 0 {
+		n += 1 + l + sovModel(uint64(l))
+	}
+	l = len(m.Secret)
+	if l > 0 {
+		n += 1 + l + sovModel(uint64(l))
+	}
+	l = len(m.RedirectUri)
+	if l > 0 {
+		n += 1 + l + sovModel(uint64(l))
+	}
+	if m.UserData != nil {
+		l = m.UserData.Size()
+		n += 1 + l + sovModel(uint64(l))
+	}
+	if m.SecretHash != nil {
+		l = m.SecretHash.Size()
+		n += 1 + l + sovModel(uint64(l))
+	}
+	if m.RefreshPolicy != nil {
+		l = m.RefreshPolicy.Size()
+		n += 1 + l + sovModel(uint64(l))
+	}
+	if len(m.Metadata) > 0 {
+		for k, v := range m.Metadata {
+			_ = k
+			_ = v
+			l = 0
+			if len(v) > 0 {
+				l = 1 + len(v) + sovModel(uint64(len(v)))
+			}
+			mapEntrySize := 1 + len(k) + sovModel(uint64(len(k))) + l
+			n += mapEntrySize + 1 + sovModel(uint64(mapEntrySize))
+		}
+	}
+	if m.ClientMetadata != nil {
+		l = m.ClientMetadata.Size()
+		n += 1 + l + sovModel(uint64(l))
+	}
+	l = len(m.RegistrationToken)
+	if l > 0 {
+		n += 1 + l + sovModel(uint64(l))
+	}
+	if len(m.Secrets) > 0 {
+		for _, e := range m.Secrets {
+			l = e.Size()
+			n += 1 + l + sovModel(uint64(l))
+		}
+	}
+	l = len(m.CreatedAt)
+	if l > 0 {
+		n += 1 + l + sovModel(uint64(l))
+	}
+	return n
+}
+
+func (m *RefreshPolicy) Size() (n int) {
+	var l int
+	_ = l
+	if m.MaxLifetime != 0 {
+		n += 1 + sovModel(uint64(m.MaxLifetime))
+	}
+	if m.IdleTimeout != 0 {
+		n += 1 + sovModel(uint64(m.IdleTimeout))
+	}
+	return n
+}
+
+func (m *AuthorizeData) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.ClientId)
+	if l > 0 {
+		n += 1 + l + sovModel(uint64(l))
+	}
+	l = len(m.Code)
+	if l > 0 {
+		n += 1 + l + sovModel(uint64(l))
+	}
+	if m.ExpiresIn != 0 {
+		n += 1 + sovModel(uint64(m.ExpiresIn))
+	}
+	l = len(m.Scope)
+	if l > 0 {
+		n += 1 + l + sovModel(uint64(l))
+	}
+	l = len(m.RedirectUri)
+	if l > 0 {
+		n += 1 + l + sovModel(uint64(l))
+	}
+	l = len(m.State)
+	if l > 0 {
+		n += 1 + l + sovModel(uint64(l))
+	}
+	l = len(m.CreatedAt)
+	if l > 0 {
+		n += 1 + l + sovModel(uint64(l))
+	}
+	if m.UserData != nil {
+		l = m.UserData.Size()
+		n += 1 + l + sovModel(uint64(l))
+	}
+	l = len(m.CodeChallenge)
+	if l > 0 {
+		n += 1 + l + sovModel(uint64(l))
+	}
+	l = len(m.CodeChallengeMethod)
+	if l > 0 {
+		n += 1 + l + sovModel(uint64(l))
+	}
+	if m.Used {
+		n += 2
+	}
+	if len(m.AccessTokens) > 0 {
+		for _, s := range m.AccessTokens {
+			l = len(s)
+			n += 1 + l + sovModel(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *AccessData) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.ClientId)
 	if l > 0 {
 		n += 1 + l + sovModel(uint64(l))
 	}
@@ -704,56 +1634,1610 @@ func (m *AccessData) Size() (n int) {
 		l = m.UserData.Size()
 		n += 1 + l + sovModel(uint64(l))
 	}
+	l = len(m.FamilyId)
+	if l > 0 {
+		n += 1 + l + sovModel(uint64(l))
+	}
+	l = len(m.FamilyCreatedAt)
+	if l > 0 {
+		n += 1 + l + sovModel(uint64(l))
+	}
+	if m.Origin != nil {
+		l = m.Origin.Size()
+		n += 1 + l + sovModel(uint64(l))
+	}
 	return n
 }
 
-func sovModel(x uint64) (n int) {
-	for {
-		n++
-		x >>= 7
-		if x == 0 {
-			break
-		}
+func (m *RefreshFamily) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.Id)
+	if l > 0 {
+		n += 1 + l + sovModel(uint64(l))
+	}
+	l = len(m.ClientId)
+	if l > 0 {
+		n += 1 + l + sovModel(uint64(l))
+	}
+	l = len(m.RefreshToken)
+	if l > 0 {
+		n += 1 + l + sovModel(uint64(l))
 	}
 	return n
 }
-func sozModel(x uint64) (n int) {
-	return sovModel(uint64((x << 1) ^ uint64((int64(x) >> 63))))
-}
-func (m *UserData) Unmarshal(dAtA []byte) error {
-	l := len(dAtA)
-	iNdEx := 0
-	for iNdEx < l {
-		preIndex := iNdEx
-		var wire uint64
-		for shift := uint(0); ; shift += 7 {
-			if shift >= 64 {
-				return ErrIntOverflowModel
+
+func (m *ClientMetadata) Size() (n int) {
+	var l int
+	_ = l
+	if len(m.RedirectUris) > 0 {
+		for _, s := range m.RedirectUris {
+			l = len(s)
+			n += 1 + l + sovModel(uint64(l))
+		}
+	}
+	l = len(m.TokenEndpointAuthMethod)
+	if l > 0 {
+		n += 1 + l + sovModel(uint64(l))
+	}
+	if len(m.GrantTypes) > 0 {
+		for _, s := range m.GrantTypes {
+			l = len(s)
+			n += 1 + l + sovModel(uint64(l))
+		}
+	}
+	if len(m.ResponseTypes) > 0 {
+		for _, s := range m.ResponseTypes {
+			l = len(s)
+			n += 1 + l + sovModel(uint64(l))
+		}
+	}
+	l = len(m.ClientName)
+	if l > 0 {
+		n += 1 + l + sovModel(uint64(l))
+	}
+	l = len(m.ClientUri)
+	if l > 0 {
+		n += 1 + l + sovModel(uint64(l))
+	}
+	l = len(m.LogoUri)
+	if l > 0 {
+		n += 1 + l + sovModel(uint64(l))
+	}
+	l = len(m.Scope)
+	if l > 0 {
+		n += 1 + l + sovModel(uint64(l))
+	}
+	if len(m.Contacts) > 0 {
+		for _, s := range m.Contacts {
+			l = len(s)
+			n += 1 + l + sovModel(uint64(l))
+		}
+	}
+	l = len(m.TosUri)
+	if l > 0 {
+		n += 1 + l + sovModel(uint64(l))
+	}
+	l = len(m.PolicyUri)
+	if l > 0 {
+		n += 1 + l + sovModel(uint64(l))
+	}
+	l = len(m.JwksUri)
+	if l > 0 {
+		n += 1 + l + sovModel(uint64(l))
+	}
+	l = len(m.Jwks)
+	if l > 0 {
+		n += 1 + l + sovModel(uint64(l))
+	}
+	l = len(m.SoftwareId)
+	if l > 0 {
+		n += 1 + l + sovModel(uint64(l))
+	}
+	l = len(m.SoftwareVersion)
+	if l > 0 {
+		n += 1 + l + sovModel(uint64(l))
+	}
+	return n
+}
+
+func (m *ClientSecret) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.Id)
+	if l > 0 {
+		n += 1 + l + sovModel(uint64(l))
+	}
+	l = len(m.Secret)
+	if l > 0 {
+		n += 1 + l + sovModel(uint64(l))
+	}
+	if m.SecretHash != nil {
+		l = m.SecretHash.Size()
+		n += 1 + l + sovModel(uint64(l))
+	}
+	l = len(m.CreatedAt)
+	if l > 0 {
+		n += 1 + l + sovModel(uint64(l))
+	}
+	l = len(m.ExpiresAt)
+	if l > 0 {
+		n += 1 + l + sovModel(uint64(l))
+	}
+	return n
+}
+
+func sovModel(x uint64) (n int) {
+	for {
+		n++
+		x >>= 7
+		if x == 0 {
+			break
+		}
+	}
+	return n
+}
+func sozModel(x uint64) (n int) {
+	return sovModel(uint64((x << 1) ^ uint64((int64(x) >> 63))))
+}
+func (m *UserData) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowModel
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: UserData: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: UserData: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Type", wireType)
+			}
+			m.Type = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowModel
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Type |= (UserData_Type(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Name", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowModel
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthModel
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Name = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Data", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowModel
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthModel
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Data = append(m.Data[:0], dAtA[iNdEx:postIndex]...)
+			if m.Data == nil {
+				m.Data = []byte{}
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipModel(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthModel
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *SecretHash) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowModel
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: SecretHash: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: SecretHash: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Algorithm", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowModel
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthModel
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Algorithm = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Salt", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowModel
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthModel
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Salt = append(m.Salt[:0], dAtA[iNdEx:postIndex]...)
+			if m.Salt == nil {
+				m.Salt = []byte{}
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Hash", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowModel
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthModel
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Hash = append(m.Hash[:0], dAtA[iNdEx:postIndex]...)
+			if m.Hash == nil {
+				m.Hash = []byte{}
+			}
+			iNdEx = postIndex
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Iterations", wireType)
+			}
+			m.Iterations = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowModel
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Iterations |= (uint32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Memory", wireType)
+			}
+			m.Memory = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowModel
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Memory |= (uint32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Threads", wireType)
+			}
+			m.Threads = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowModel
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Threads |= (uint32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipModel(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthModel
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Client) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowModel
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Client: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Client: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Id", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowModel
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthModel
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Id = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Secret", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowModel
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthModel
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Secret = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RedirectUri", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowModel
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthModel
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.RedirectUri = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field UserData", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowModel
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthModel
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.UserData == nil {
+				m.UserData = &UserData{}
+			}
+			if err := m.UserData.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SecretHash", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowModel
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthModel
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.SecretHash == nil {
+				m.SecretHash = &SecretHash{}
+			}
+			if err := m.SecretHash.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RefreshPolicy", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowModel
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthModel
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.RefreshPolicy == nil {
+				m.RefreshPolicy = &RefreshPolicy{}
+			}
+			if err := m.RefreshPolicy.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 7:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Metadata", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowModel
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthModel
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Metadata == nil {
+				m.Metadata = make(map[string][]byte)
+			}
+			var mapkey string
+			mapvalue := []byte{}
+			for iNdEx < postIndex {
+				entryPreIndex := iNdEx
+				var wire uint64
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowModel
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					wire |= (uint64(b) & 0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				fieldNum := int32(wire >> 3)
+				if fieldNum == 1 {
+					var stringLenmapkey uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowModel
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapkey |= (uint64(b) & 0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapkey := int(stringLenmapkey)
+					if intStringLenmapkey < 0 {
+						return ErrInvalidLengthModel
+					}
+					postStringIndexmapkey := iNdEx + intStringLenmapkey
+					if postStringIndexmapkey > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapkey = string(dAtA[iNdEx:postStringIndexmapkey])
+					iNdEx = postStringIndexmapkey
+				} else if fieldNum == 2 {
+					var mapbyteLen uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowModel
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						mapbyteLen |= (uint64(b) & 0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intMapbyteLen := int(mapbyteLen)
+					if intMapbyteLen < 0 {
+						return ErrInvalidLengthModel
+					}
+					postbytesIndex := iNdEx + intMapbyteLen
+					if postbytesIndex > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapvalue = make([]byte, mapbyteLen)
+					copy(mapvalue, dAtA[iNdEx:postbytesIndex])
+					iNdEx = postbytesIndex
+				} else {
+					iNdEx = entryPreIndex
+					skippy, err := skipModel(dAtA[iNdEx:])
+					if err != nil {
+						return err
+					}
+					if skippy < 0 {
+						return ErrInvalidLengthModel
+					}
+					if (iNdEx + skippy) > postIndex {
+						return io.ErrUnexpectedEOF
+					}
+					iNdEx += skippy
+				}
+			}
+			m.Metadata[mapkey] = mapvalue
+			iNdEx = postIndex
+		case 8:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ClientMetadata", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowModel
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthModel
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.ClientMetadata == nil {
+				m.ClientMetadata = &ClientMetadata{}
+			}
+			if err := m.ClientMetadata.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 9:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RegistrationToken", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowModel
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthModel
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.RegistrationToken = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 10:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Secrets", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowModel
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthModel
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Secrets = append(m.Secrets, &ClientSecret{})
+			if err := m.Secrets[len(m.Secrets)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 11:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CreatedAt", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowModel
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthModel
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.CreatedAt = append(m.CreatedAt[:0], dAtA[iNdEx:postIndex]...)
+			if m.CreatedAt == nil {
+				m.CreatedAt = []byte{}
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipModel(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthModel
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *RefreshPolicy) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowModel
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: RefreshPolicy: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: RefreshPolicy: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MaxLifetime", wireType)
+			}
+			m.MaxLifetime = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowModel
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.MaxLifetime |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field IdleTimeout", wireType)
+			}
+			m.IdleTimeout = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowModel
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.IdleTimeout |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipModel(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthModel
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *AuthorizeData) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowModel
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: AuthorizeData: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: AuthorizeData: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ClientId", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowModel
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthModel
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ClientId = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Code", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowModel
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthModel
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Code = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ExpiresIn", wireType)
+			}
+			m.ExpiresIn = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowModel
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ExpiresIn |= (int32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Scope", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowModel
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthModel
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Scope = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RedirectUri", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowModel
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthModel
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.RedirectUri = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field State", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowModel
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthModel
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.State = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 7:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CreatedAt", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowModel
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthModel
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.CreatedAt = append(m.CreatedAt[:0], dAtA[iNdEx:postIndex]...)
+			if m.CreatedAt == nil {
+				m.CreatedAt = []byte{}
+			}
+			iNdEx = postIndex
+		case 8:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field UserData", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowModel
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthModel
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.UserData == nil {
+				m.UserData = &UserData{}
+			}
+			if err := m.UserData.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 9:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CodeChallenge", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowModel
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthModel
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.CodeChallenge = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 10:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CodeChallengeMethod", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowModel
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthModel
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.CodeChallengeMethod = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 11:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Used", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowModel
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Used = bool(v != 0)
+		case 12:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field AccessTokens", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowModel
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthModel
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.AccessTokens = append(m.AccessTokens, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipModel(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthModel
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *AccessData) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowModel
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: AccessData: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: AccessData: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ClientId", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowModel
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthModel
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ClientId = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field AuthorizeCode", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowModel
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthModel
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.AuthorizeCode = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field PrevAccessToken", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowModel
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthModel
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.PrevAccessToken = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field AccessToken", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowModel
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
 			}
-			if iNdEx >= l {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthModel
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			b := dAtA[iNdEx]
-			iNdEx++
-			wire |= (uint64(b) & 0x7F) << shift
-			if b < 0x80 {
-				break
+			m.AccessToken = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RefreshToken", wireType)
 			}
-		}
-		fieldNum := int32(wire >> 3)
-		wireType := int(wire & 0x7)
-		if wireType == 4 {
-			return fmt.Errorf("proto: UserData: wiretype end group for non-group")
-		}
-		if fieldNum <= 0 {
-			return fmt.Errorf("proto: UserData: illegal tag %d (wire type %d)", fieldNum, wire)
-		}
-		switch fieldNum {
-		case 1:
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowModel
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthModel
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.RefreshToken = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 6:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Type", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field ExpiresIn", wireType)
 			}
-			m.Type = 0
+			m.ExpiresIn = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowModel
@@ -763,14 +3247,14 @@ func (m *UserData) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.Type |= (UserData_Type(b) & 0x7F) << shift
+				m.ExpiresIn |= (int32(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-		case 2:
+		case 7:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Name", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Scope", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -795,11 +3279,40 @@ func (m *UserData) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Name = string(dAtA[iNdEx:postIndex])
+			m.Scope = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		case 3:
+		case 8:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Data", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field RedirectUri", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowModel
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthModel
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.RedirectUri = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 9:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CreatedAt", wireType)
 			}
 			var byteLen int
 			for shift := uint(0); ; shift += 7 {
@@ -823,9 +3336,135 @@ func (m *UserData) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Data = append(m.Data[:0], dAtA[iNdEx:postIndex]...)
-			if m.Data == nil {
-				m.Data = []byte{}
+			m.CreatedAt = append(m.CreatedAt[:0], dAtA[iNdEx:postIndex]...)
+			if m.CreatedAt == nil {
+				m.CreatedAt = []byte{}
+			}
+			iNdEx = postIndex
+		case 10:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field UserData", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowModel
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthModel
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.UserData == nil {
+				m.UserData = &UserData{}
+			}
+			if err := m.UserData.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 11:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field FamilyId", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowModel
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthModel
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.FamilyId = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 12:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field FamilyCreatedAt", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowModel
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthModel
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.FamilyCreatedAt = append(m.FamilyCreatedAt[:0], dAtA[iNdEx:postIndex]...)
+			if m.FamilyCreatedAt == nil {
+				m.FamilyCreatedAt = []byte{}
+			}
+			iNdEx = postIndex
+		case 13:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Origin", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowModel
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthModel
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Origin == nil {
+				m.Origin = &AuthorizeData{}
+			}
+			if err := m.Origin.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
 			}
 			iNdEx = postIndex
 		default:
@@ -849,7 +3488,7 @@ func (m *UserData) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *Client) Unmarshal(dAtA []byte) error {
+func (m *RefreshFamily) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -872,10 +3511,10 @@ func (m *Client) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: Client: wiretype end group for non-group")
+			return fmt.Errorf("proto: RefreshFamily: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: Client: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: RefreshFamily: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
@@ -909,7 +3548,7 @@ func (m *Client) Unmarshal(dAtA []byte) error {
 			iNdEx = postIndex
 		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Secret", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field ClientId", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -934,11 +3573,11 @@ func (m *Client) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Secret = string(dAtA[iNdEx:postIndex])
+			m.ClientId = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		case 3:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field RedirectUri", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field RefreshToken", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -959,44 +3598,11 @@ func (m *Client) Unmarshal(dAtA []byte) error {
 			if intStringLen < 0 {
 				return ErrInvalidLengthModel
 			}
-			postIndex := iNdEx + intStringLen
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			m.RedirectUri = string(dAtA[iNdEx:postIndex])
-			iNdEx = postIndex
-		case 4:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field UserData", wireType)
-			}
-			var msglen int
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowModel
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				msglen |= (int(b) & 0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-			if msglen < 0 {
-				return ErrInvalidLengthModel
-			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + intStringLen
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.UserData == nil {
-				m.UserData = &UserData{}
-			}
-			if err := m.UserData.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
+			m.RefreshToken = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
@@ -1019,7 +3625,7 @@ func (m *Client) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *AuthorizeData) Unmarshal(dAtA []byte) error {
+func (m *ClientMetadata) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -1042,15 +3648,15 @@ func (m *AuthorizeData) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: AuthorizeData: wiretype end group for non-group")
+			return fmt.Errorf("proto: ClientMetadata: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: AuthorizeData: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: ClientMetadata: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field ClientId", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field RedirectUris", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -1075,11 +3681,11 @@ func (m *AuthorizeData) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.ClientId = string(dAtA[iNdEx:postIndex])
+			m.RedirectUris = append(m.RedirectUris, string(dAtA[iNdEx:postIndex]))
 			iNdEx = postIndex
 		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Code", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field TokenEndpointAuthMethod", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -1104,13 +3710,13 @@ func (m *AuthorizeData) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Code = string(dAtA[iNdEx:postIndex])
+			m.TokenEndpointAuthMethod = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		case 3:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field ExpiresIn", wireType)
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field GrantTypes", wireType)
 			}
-			m.ExpiresIn = 0
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowModel
@@ -1120,14 +3726,24 @@ func (m *AuthorizeData) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.ExpiresIn |= (int32(b) & 0x7F) << shift
+				stringLen |= (uint64(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthModel
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.GrantTypes = append(m.GrantTypes, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
 		case 4:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Scope", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field ResponseTypes", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -1152,11 +3768,11 @@ func (m *AuthorizeData) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Scope = string(dAtA[iNdEx:postIndex])
+			m.ResponseTypes = append(m.ResponseTypes, string(dAtA[iNdEx:postIndex]))
 			iNdEx = postIndex
 		case 5:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field RedirectUri", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field ClientName", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -1181,11 +3797,11 @@ func (m *AuthorizeData) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.RedirectUri = string(dAtA[iNdEx:postIndex])
+			m.ClientName = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		case 6:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field State", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field ClientUri", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -1210,13 +3826,13 @@ func (m *AuthorizeData) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.State = string(dAtA[iNdEx:postIndex])
+			m.ClientUri = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		case 7:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field CreatedAt", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field LogoUri", wireType)
 			}
-			var byteLen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowModel
@@ -1226,28 +3842,26 @@ func (m *AuthorizeData) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				byteLen |= (int(b) & 0x7F) << shift
+				stringLen |= (uint64(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if byteLen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthModel
 			}
-			postIndex := iNdEx + byteLen
+			postIndex := iNdEx + intStringLen
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.CreatedAt = append(m.CreatedAt[:0], dAtA[iNdEx:postIndex]...)
-			if m.CreatedAt == nil {
-				m.CreatedAt = []byte{}
-			}
+			m.LogoUri = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		case 8:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field UserData", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Scope", wireType)
 			}
-			var msglen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowModel
@@ -1257,28 +3871,24 @@ func (m *AuthorizeData) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= (int(b) & 0x7F) << shift
+				stringLen |= (uint64(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthModel
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + intStringLen
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.UserData == nil {
-				m.UserData = &UserData{}
-			}
-			if err := m.UserData.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
+			m.Scope = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		case 9:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field CodeChallenge", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Contacts", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -1303,11 +3913,11 @@ func (m *AuthorizeData) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.CodeChallenge = string(dAtA[iNdEx:postIndex])
+			m.Contacts = append(m.Contacts, string(dAtA[iNdEx:postIndex]))
 			iNdEx = postIndex
 		case 10:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field CodeChallengeMethod", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field TosUri", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -1332,61 +3942,11 @@ func (m *AuthorizeData) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.CodeChallengeMethod = string(dAtA[iNdEx:postIndex])
+			m.TosUri = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		default:
-			iNdEx = preIndex
-			skippy, err := skipModel(dAtA[iNdEx:])
-			if err != nil {
-				return err
-			}
-			if skippy < 0 {
-				return ErrInvalidLengthModel
-			}
-			if (iNdEx + skippy) > l {
-				return io.ErrUnexpectedEOF
-			}
-			iNdEx += skippy
-		}
-	}
-
-	if iNdEx > l {
-		return io.ErrUnexpectedEOF
-	}
-	return nil
-}
-func (m *AccessData) Unmarshal(dAtA []byte) error {
-	l := len(dAtA)
-	iNdEx := 0
-	for iNdEx < l {
-		preIndex := iNdEx
-		var wire uint64
-		for shift := uint(0); ; shift += 7 {
-			if shift >= 64 {
-				return ErrIntOverflowModel
-			}
-			if iNdEx >= l {
-				return io.ErrUnexpectedEOF
-			}
-			b := dAtA[iNdEx]
-			iNdEx++
-			wire |= (uint64(b) & 0x7F) << shift
-			if b < 0x80 {
-				break
-			}
-		}
-		fieldNum := int32(wire >> 3)
-		wireType := int(wire & 0x7)
-		if wireType == 4 {
-			return fmt.Errorf("proto: AccessData: wiretype end group for non-group")
-		}
-		if fieldNum <= 0 {
-			return fmt.Errorf("proto: AccessData: illegal tag %d (wire type %d)", fieldNum, wire)
-		}
-		switch fieldNum {
-		case 1:
+		case 11:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field ClientId", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field PolicyUri", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -1411,11 +3971,11 @@ func (m *AccessData) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.ClientId = string(dAtA[iNdEx:postIndex])
+			m.PolicyUri = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		case 2:
+		case 12:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field AuthorizeCode", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field JwksUri", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -1440,13 +4000,13 @@ func (m *AccessData) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.AuthorizeCode = string(dAtA[iNdEx:postIndex])
+			m.JwksUri = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		case 3:
+		case 13:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field PrevAccessToken", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Jwks", wireType)
 			}
-			var stringLen uint64
+			var byteLen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowModel
@@ -1456,24 +4016,26 @@ func (m *AccessData) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= (uint64(b) & 0x7F) << shift
+				byteLen |= (int(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
+			if byteLen < 0 {
 				return ErrInvalidLengthModel
 			}
-			postIndex := iNdEx + intStringLen
+			postIndex := iNdEx + byteLen
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.PrevAccessToken = string(dAtA[iNdEx:postIndex])
+			m.Jwks = append(m.Jwks[:0], dAtA[iNdEx:postIndex]...)
+			if m.Jwks == nil {
+				m.Jwks = []byte{}
+			}
 			iNdEx = postIndex
-		case 4:
+		case 14:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field AccessToken", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field SoftwareId", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -1498,11 +4060,11 @@ func (m *AccessData) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.AccessToken = string(dAtA[iNdEx:postIndex])
+			m.SoftwareId = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		case 5:
+		case 15:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field RefreshToken", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field SoftwareVersion", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -1527,13 +4089,63 @@ func (m *AccessData) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.RefreshToken = string(dAtA[iNdEx:postIndex])
+			m.SoftwareVersion = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		case 6:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field ExpiresIn", wireType)
+		default:
+			iNdEx = preIndex
+			skippy, err := skipModel(dAtA[iNdEx:])
+			if err != nil {
+				return err
 			}
-			m.ExpiresIn = 0
+			if skippy < 0 {
+				return ErrInvalidLengthModel
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *ClientSecret) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowModel
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ClientSecret: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ClientSecret: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Id", wireType)
+			}
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowModel
@@ -1543,14 +4155,24 @@ func (m *AccessData) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.ExpiresIn |= (int32(b) & 0x7F) << shift
+				stringLen |= (uint64(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-		case 7:
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthModel
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Id = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Scope", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Secret", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -1575,13 +4197,13 @@ func (m *AccessData) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Scope = string(dAtA[iNdEx:postIndex])
+			m.Secret = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		case 8:
+		case 3:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field RedirectUri", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field SecretHash", wireType)
 			}
-			var stringLen uint64
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowModel
@@ -1591,22 +4213,26 @@ func (m *AccessData) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= (uint64(b) & 0x7F) << shift
+				msglen |= (int(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
+			if msglen < 0 {
 				return ErrInvalidLengthModel
 			}
-			postIndex := iNdEx + intStringLen
+			postIndex := iNdEx + msglen
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.RedirectUri = string(dAtA[iNdEx:postIndex])
+			if m.SecretHash == nil {
+				m.SecretHash = &SecretHash{}
+			}
+			if err := m.SecretHash.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
 			iNdEx = postIndex
-		case 9:
+		case 4:
 			if wireType != 2 {
 				return fmt.Errorf("proto: wrong wireType = %d for field CreatedAt", wireType)
 			}
@@ -1637,11 +4263,11 @@ func (m *AccessData) Unmarshal(dAtA []byte) error {
 				m.CreatedAt = []byte{}
 			}
 			iNdEx = postIndex
-		case 10:
+		case 5:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field UserData", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field ExpiresAt", wireType)
 			}
-			var msglen int
+			var byteLen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowModel
@@ -1651,23 +4277,21 @@ func (m *AccessData) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= (int(b) & 0x7F) << shift
+				byteLen |= (int(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			if byteLen < 0 {
 				return ErrInvalidLengthModel
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + byteLen
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.UserData == nil {
-				m.UserData = &UserData{}
-			}
-			if err := m.UserData.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
+			m.ExpiresAt = append(m.ExpiresAt[:0], dAtA[iNdEx:postIndex]...)
+			if m.ExpiresAt == nil {
+				m.ExpiresAt = []byte{}
 			}
 			iNdEx = postIndex
 		default:
@@ -1799,39 +4423,81 @@ var (
 func init() { proto.RegisterFile("model.proto", fileDescriptorModel) }
 
 var fileDescriptorModel = []byte{
-	// 538 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x84, 0x54, 0xcd, 0xae, 0xd2, 0x40,
-	0x18, 0xb5, 0xa5, 0x2d, 0x9d, 0x8f, 0x9f, 0x5b, 0xc7, 0xab, 0x69, 0x62, 0x24, 0x88, 0xb9, 0x09,
-	0x31, 0x86, 0x05, 0x3e, 0x01, 0xe0, 0x4f, 0x48, 0x10, 0x4c, 0x6f, 0x59, 0xdc, 0x55, 0x33, 0xb6,
-	0x9f, 0xd2, 0x08, 0x6d, 0x33, 0x1d, 0x8c, 0xd7, 0x9d, 0x4f, 0xe5, 0x2b, 0xb8, 0x74, 0xe5, 0xda,
-	0xb0, 0xf3, 0x2d, 0xcc, 0x4c, 0x0b, 0xb9, 0x95, 0xdc, 0xcb, 0xee, 0xcc, 0x99, 0xf3, 0x31, 0xe7,
-	0x3b, 0x87, 0x14, 0x1a, 0x9b, 0x34, 0xc2, 0xf5, 0x20, 0xe3, 0xa9, 0x48, 0xa9, 0xa9, 0x0e, 0xbd,
-	0x1f, 0x1a, 0xd8, 0xcb, 0x1c, 0xf9, 0x2b, 0x26, 0x18, 0xed, 0x83, 0x21, 0xae, 0x33, 0x74, 0xb5,
-	0xae, 0xd6, 0x6f, 0x0f, 0xcf, 0x07, 0x85, 0x7e, 0x7f, 0x3d, 0xf0, 0xaf, 0x33, 0xf4, 0x94, 0x82,
-	0x52, 0x30, 0x12, 0xb6, 0x41, 0x57, 0xef, 0x6a, 0x7d, 0xe2, 0x29, 0x2c, 0xb9, 0x88, 0x09, 0xe6,
-	0xd6, 0xba, 0x5a, 0xbf, 0xe9, 0x29, 0xdc, 0xbb, 0x02, 0x43, 0x4e, 0xd1, 0x3a, 0xd4, 0xe6, 0xd3,
-	0x99, 0x73, 0x8f, 0x12, 0x30, 0xdf, 0x7b, 0x0b, 0x7f, 0xe1, 0x68, 0x12, 0x8e, 0xaf, 0xfc, 0xd7,
-	0x97, 0x8e, 0x4e, 0x01, 0xac, 0x4b, 0xdf, 0x9b, 0xce, 0xdf, 0x3a, 0x35, 0x29, 0x9d, 0xce, 0x7d,
-	0xc7, 0xa0, 0x36, 0x18, 0x4b, 0x89, 0x4c, 0x89, 0xc6, 0x8b, 0xc5, 0xcc, 0xb1, 0xe4, 0xcc, 0x9b,
-	0xd9, 0x62, 0xe4, 0x3b, 0xf5, 0xde, 0x77, 0x0d, 0xac, 0xc9, 0x3a, 0xc6, 0x44, 0xd0, 0x36, 0xe8,
-	0x71, 0xa4, 0x5c, 0x13, 0x4f, 0x8f, 0x23, 0xfa, 0x08, 0xac, 0x1c, 0x43, 0x8e, 0xa2, 0xf4, 0x57,
-	0x9e, 0xe8, 0x53, 0x68, 0x72, 0x8c, 0x62, 0x8e, 0xa1, 0x08, 0xb6, 0x3c, 0x56, 0x4e, 0x89, 0xd7,
-	0xd8, 0x73, 0x4b, 0x1e, 0xd3, 0x17, 0x40, 0xb6, 0x39, 0xf2, 0x40, 0x6d, 0x62, 0x74, 0xb5, 0x7e,
-	0x63, 0x78, 0xf6, 0x5f, 0x0e, 0x9e, 0xbd, 0x2d, 0x51, 0xef, 0xb7, 0x0e, 0xad, 0xd1, 0x56, 0xac,
-	0x52, 0x1e, 0x7f, 0x43, 0x15, 0xe1, 0x63, 0x20, 0xa1, 0x32, 0x15, 0x1c, 0x1c, 0xd9, 0x05, 0x31,
-	0x8d, 0x64, 0x42, 0x61, 0x1a, 0x1d, 0x52, 0x93, 0x98, 0x3e, 0x01, 0xc0, 0xaf, 0x59, 0xcc, 0x31,
-	0x0f, 0xe2, 0x44, 0x39, 0x32, 0x3d, 0x52, 0x32, 0xd3, 0x84, 0x9e, 0x83, 0x99, 0x87, 0x69, 0x86,
-	0xca, 0x0b, 0xf1, 0x8a, 0xc3, 0xd1, 0x22, 0xe6, 0xf1, 0x22, 0x72, 0x50, 0x30, 0x81, 0xae, 0x55,
-	0x0e, 0xca, 0x83, 0x7c, 0x2d, 0xe4, 0xc8, 0x04, 0x46, 0x01, 0x13, 0x6e, 0x5d, 0x35, 0x45, 0x4a,
-	0x66, 0x24, 0xaa, 0xdb, 0xdb, 0x27, 0xb6, 0xa7, 0x17, 0xd0, 0x96, 0x2b, 0x04, 0xe1, 0x8a, 0xad,
-	0xd7, 0x98, 0x7c, 0x42, 0x97, 0xa8, 0xb7, 0x5a, 0x92, 0x9d, 0xec, 0x49, 0x3a, 0x84, 0x87, 0x55,
-	0x59, 0xb0, 0x41, 0xb1, 0x4a, 0x23, 0x17, 0x94, 0xfa, 0x41, 0x45, 0xfd, 0x4e, 0x5d, 0xf5, 0xfe,
-	0xea, 0x00, 0xa3, 0x30, 0xc4, 0x3c, 0x3f, 0x9d, 0xea, 0x05, 0xb4, 0xd9, 0xbe, 0x83, 0xe0, 0x46,
-	0xbe, 0xad, 0x03, 0x3b, 0x91, 0x41, 0x3f, 0x87, 0xfb, 0x19, 0xc7, 0x2f, 0x01, 0x53, 0x3f, 0x1b,
-	0x88, 0xf4, 0x33, 0x26, 0xe5, 0x3f, 0xe0, 0x4c, 0x5e, 0x14, 0xcf, 0xf9, 0x92, 0x96, 0xf9, 0x56,
-	0x64, 0x45, 0xf8, 0x0d, 0x76, 0x43, 0xf2, 0x0c, 0x5a, 0x1c, 0x3f, 0x72, 0xcc, 0x57, 0xa5, 0xa6,
-	0xe8, 0xa0, 0x59, 0x92, 0x85, 0xa8, 0x5a, 0xae, 0x75, 0x6b, 0xb9, 0xf5, 0xbb, 0xca, 0xb5, 0x8f,
-	0xcb, 0xad, 0xd6, 0x48, 0xee, 0xac, 0x11, 0x4e, 0xd4, 0x38, 0x6e, 0xfe, 0xdc, 0x75, 0xb4, 0x5f,
-	0xbb, 0x8e, 0xf6, 0x67, 0xd7, 0xd1, 0x3e, 0x58, 0xea, 0xf3, 0xf0, 0xf2, 0x5f, 0x00, 0x00, 0x00,
-	0xff, 0xff, 0x02, 0xd5, 0x41, 0xfb, 0x2d, 0x04, 0x00, 0x00,
+	// 1204 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0x03, 0x8d, 0x56, 0xdd, 0x6e, 0x1b, 0x45,
+	0x14, 0xae, 0xb3, 0xfe, 0xd9, 0x3d, 0xfe, 0xdb, 0x4c, 0x5b, 0xba, 0xa4, 0x90, 0x16, 0x23, 0xa4,
+	0x82, 0x20, 0x17, 0xe1, 0x02, 0x44, 0x25, 0x24, 0x27, 0x4d, 0xc1, 0x28, 0x89, 0xa3, 0x8d, 0x83,
+	0xc4, 0xd5, 0x6a, 0x59, 0x4f, 0xec, 0x25, 0x6b, 0xaf, 0xb5, 0x33, 0x4e, 0x6b, 0x1e, 0x02, 0x78,
+	0x08, 0x90, 0x78, 0x14, 0x2e, 0x79, 0x84, 0x0a, 0xae, 0x79, 0x01, 0xae, 0x38, 0x67, 0x66, 0xd6,
+	0xf1, 0xda, 0xa2, 0xe9, 0xc5, 0x4a, 0xe7, 0x7c, 0xe7, 0x9c, 0x99, 0xf3, 0xf3, 0xcd, 0xec, 0x40,
+	0x7d, 0x92, 0x0e, 0x79, 0xb2, 0x37, 0xcb, 0x52, 0x99, 0xb2, 0x8a, 0x52, 0x3a, 0xff, 0x94, 0xc0,
+	0xbe, 0x10, 0x3c, 0x7b, 0x16, 0xca, 0x90, 0x3d, 0x81, 0xb2, 0x5c, 0xcc, 0xb8, 0x57, 0x7a, 0x5c,
+	0x7a, 0xd2, 0xda, 0xbf, 0xb7, 0xa7, 0xfd, 0x73, 0xf3, 0xde, 0x00, 0x6d, 0xbe, 0xf2, 0x60, 0x0c,
+	0xca, 0xd3, 0x70, 0xc2, 0xbd, 0x2d, 0xf4, 0x74, 0x7c, 0x25, 0x13, 0x36, 0x44, 0x37, 0xcf, 0x42,
+	0xac, 0xe1, 0x2b, 0xb9, 0xf3, 0x53, 0x09, 0xca, 0x14, 0xc6, 0x6a, 0x60, 0x9d, 0xf6, 0x8e, 0xdd,
+	0x3b, 0xcc, 0x81, 0xca, 0x99, 0xdf, 0x1f, 0xf4, 0xdd, 0x12, 0x89, 0x07, 0xdf, 0x0d, 0x8e, 0xce,
+	0xdd, 0x2d, 0x06, 0x50, 0x3d, 0x1f, 0xf8, 0xbd, 0xd3, 0xaf, 0x5c, 0x8b, 0x5c, 0x7b, 0xa7, 0x03,
+	0xb7, 0xcc, 0x6c, 0x28, 0x5f, 0x90, 0x54, 0x21, 0xe9, 0xa0, 0xdf, 0x3f, 0x76, 0xab, 0x14, 0xf3,
+	0xfc, 0xb8, 0xdf, 0x1d, 0xb8, 0x35, 0x02, 0xbf, 0x39, 0xef, 0x9f, 0xba, 0x36, 0x45, 0x9c, 0x74,
+	0xcf, 0x5c, 0x87, 0xa0, 0xe3, 0xde, 0xf9, 0xc0, 0x05, 0x92, 0x06, 0xbd, 0x93, 0x23, 0xb7, 0xce,
+	0x1a, 0x60, 0x3f, 0xbb, 0xf0, 0xbb, 0x83, 0x1e, 0xba, 0x36, 0x3a, 0xbf, 0x96, 0x00, 0xce, 0x79,
+	0x94, 0x71, 0xf9, 0x75, 0x28, 0xc6, 0xec, 0x1d, 0x70, 0xc2, 0x64, 0x94, 0x66, 0xb1, 0x1c, 0x4f,
+	0x54, 0xd9, 0x8e, 0x7f, 0x03, 0x50, 0x45, 0x22, 0x4c, 0xa4, 0xaa, 0x12, 0x2b, 0x22, 0x99, 0xb0,
+	0x31, 0x46, 0xe6, 0x55, 0x92, 0xcc, 0x76, 0x01, 0x62, 0xc9, 0xb3, 0x50, 0xc6, 0xe9, 0x54, 0x78,
+	0x65, 0xb4, 0x34, 0xfd, 0x15, 0x84, 0xbd, 0x05, 0xd5, 0x09, 0x9f, 0xa4, 0xd9, 0xc2, 0xab, 0x28,
+	0x9b, 0xd1, 0x98, 0x07, 0x35, 0x39, 0xce, 0x78, 0x38, 0x14, 0x5e, 0x55, 0x19, 0x72, 0xb5, 0xf3,
+	0x5b, 0x19, 0xaa, 0x87, 0x49, 0xcc, 0xa7, 0x92, 0xb5, 0x60, 0x2b, 0x1e, 0x9a, 0xdc, 0x50, 0xa2,
+	0xc5, 0x84, 0x2a, 0xc0, 0x34, 0xdf, 0x68, 0xec, 0x3d, 0x68, 0x64, 0x7c, 0x18, 0x67, 0x3c, 0x92,
+	0xc1, 0x3c, 0x8b, 0x55, 0x82, 0x8e, 0x5f, 0xcf, 0xb1, 0x8b, 0x2c, 0x66, 0x1f, 0x83, 0x33, 0xc7,
+	0x61, 0x06, 0x6a, 0x4c, 0x94, 0x66, 0x7d, 0xbf, 0xbd, 0x36, 0x64, 0xdf, 0x9e, 0xe7, 0x6c, 0xd8,
+	0x87, 0xba, 0x5e, 0x3a, 0x50, 0x05, 0x57, 0x94, 0xff, 0xb6, 0xf1, 0xbf, 0xe9, 0xa1, 0x0f, 0xe2,
+	0xa6, 0x9f, 0x4f, 0xa1, 0x95, 0xf1, 0xcb, 0x8c, 0x8b, 0x71, 0x30, 0x4b, 0x93, 0x38, 0x5a, 0xa8,
+	0xc2, 0xea, 0x4b, 0x2e, 0xf9, 0xda, 0x78, 0xa6, 0x6c, 0x7e, 0x33, 0x5b, 0x55, 0xd9, 0x67, 0x60,
+	0x4f, 0xb8, 0x0c, 0x55, 0x76, 0xb5, 0xc7, 0x16, 0x86, 0x3d, 0x34, 0x61, 0xba, 0x15, 0x7b, 0x27,
+	0xc6, 0x7a, 0x34, 0x95, 0xd9, 0xc2, 0x5f, 0x3a, 0xb3, 0x2f, 0xa1, 0x1d, 0x29, 0x8f, 0x60, 0x19,
+	0x6f, 0xab, 0x6d, 0xef, 0x17, 0xe2, 0xf3, 0x70, 0xbf, 0x15, 0x15, 0x74, 0xf6, 0x09, 0xb0, 0x8c,
+	0x8f, 0x62, 0x21, 0xf5, 0xc0, 0x02, 0x99, 0x5e, 0xf1, 0xa9, 0xe7, 0xa8, 0x06, 0x6e, 0xaf, 0x5a,
+	0x06, 0x64, 0x40, 0xf7, 0x9a, 0x2e, 0x59, 0x78, 0xa0, 0xd2, 0xbc, 0x5b, 0xd8, 0x46, 0xb7, 0xc6,
+	0xcf, 0x7d, 0xd8, 0xbb, 0x00, 0x28, 0x84, 0x92, 0x0f, 0x83, 0x50, 0x7a, 0x75, 0xc5, 0x1b, 0xc7,
+	0x20, 0x5d, 0xb9, 0xf3, 0x14, 0x9a, 0x85, 0xba, 0x98, 0x0b, 0xd6, 0x15, 0x5f, 0x98, 0x89, 0x93,
+	0xc8, 0xee, 0x41, 0xe5, 0x3a, 0x4c, 0xe6, 0xdc, 0x10, 0x51, 0x2b, 0x5f, 0x6c, 0x7d, 0x5e, 0xea,
+	0x5c, 0x40, 0xb3, 0xd0, 0x52, 0x62, 0xc1, 0x24, 0x7c, 0x19, 0x24, 0xf1, 0x25, 0x97, 0xf1, 0x44,
+	0x1f, 0x65, 0xcb, 0xaf, 0x23, 0x76, 0x6c, 0x20, 0x72, 0x89, 0x87, 0x09, 0x0f, 0x48, 0x49, 0xe7,
+	0x9a, 0x46, 0xe8, 0x42, 0xd8, 0x40, 0x43, 0x9d, 0x9f, 0x2d, 0x68, 0x76, 0xe7, 0x72, 0x8c, 0xe7,
+	0xe0, 0x47, 0xae, 0xc8, 0xf0, 0x10, 0x1c, 0xd3, 0xe2, 0x25, 0x19, 0x6d, 0x0d, 0xf4, 0x86, 0x74,
+	0x26, 0x22, 0x6c, 0x40, 0x7e, 0x1b, 0x90, 0x4c, 0x55, 0xf3, 0x97, 0x33, 0xa4, 0x9e, 0x08, 0xe2,
+	0xa9, 0x22, 0x63, 0xc5, 0x77, 0x0c, 0xd2, 0x9b, 0x52, 0x49, 0x22, 0x4a, 0xf1, 0xae, 0x29, 0xab,
+	0x18, 0xad, 0x6c, 0x70, 0xb8, 0xb2, 0xc9, 0x61, 0x0a, 0x94, 0xd8, 0x3a, 0x45, 0x2c, 0x0a, 0x24,
+	0x65, 0xad, 0xc7, 0xb5, 0xb5, 0x1e, 0x17, 0x89, 0x6f, 0xdf, 0x46, 0xfc, 0x0f, 0xa0, 0x45, 0x25,
+	0x04, 0xd1, 0x38, 0x4c, 0x12, 0x3e, 0x1d, 0x71, 0x43, 0x85, 0x26, 0xa1, 0x87, 0x39, 0x88, 0xe7,
+	0xe3, 0x7e, 0xd1, 0x8d, 0xd8, 0x37, 0x4e, 0x87, 0x48, 0x0a, 0xf2, 0xbe, 0x5b, 0xf0, 0x3e, 0x51,
+	0x26, 0xea, 0x14, 0x6e, 0x33, 0x54, 0x2c, 0xb0, 0x7d, 0x25, 0xb3, 0xf7, 0xa1, 0x19, 0x46, 0x11,
+	0x17, 0x42, 0xf3, 0x4e, 0x78, 0x0d, 0x24, 0x95, 0xe3, 0x37, 0x34, 0xa8, 0x28, 0x27, 0x3a, 0xaf,
+	0x2c, 0x80, 0xae, 0x02, 0x6e, 0x1f, 0x07, 0xe6, 0x1f, 0xe6, 0xc3, 0x0b, 0x56, 0x06, 0xd3, 0x5c,
+	0xa2, 0x87, 0x34, 0xa1, 0x8f, 0x60, 0x7b, 0x96, 0xf1, 0xeb, 0x60, 0x75, 0x73, 0x73, 0x6b, 0xb4,
+	0xc9, 0xd0, 0xbd, 0xd9, 0x9f, 0x06, 0x53, 0x70, 0xd3, 0x53, 0xab, 0xaf, 0xa4, 0x48, 0x65, 0xe4,
+	0x47, 0x5f, 0xfb, 0xe8, 0xe1, 0x35, 0x0c, 0xa8, 0x9d, 0x8a, 0xac, 0xa8, 0xfe, 0x2f, 0x2b, 0x6a,
+	0xaf, 0x63, 0x85, 0xbd, 0xc9, 0x8a, 0xe2, 0xfc, 0x9d, 0xd7, 0xce, 0x1f, 0x6e, 0x9b, 0x3f, 0x36,
+	0xf7, 0x32, 0x9c, 0xc4, 0xc9, 0x82, 0x9a, 0x5b, 0xd7, 0xcd, 0xd5, 0x00, 0x36, 0x17, 0xbb, 0x66,
+	0x8c, 0x2b, 0x1b, 0x36, 0xd4, 0x86, 0x6d, 0x6d, 0x38, 0x5c, 0xd9, 0xb6, 0x8a, 0xed, 0x1e, 0x61,
+	0xa5, 0xcd, 0xc2, 0x2d, 0x58, 0x38, 0x5a, 0xbe, 0xf1, 0xe9, 0x84, 0xcb, 0xb3, 0xfc, 0x5c, 0xad,
+	0xb3, 0x71, 0xf3, 0x17, 0x86, 0xbe, 0xb5, 0x36, 0xf4, 0x8d, 0xf6, 0x5b, 0x9b, 0xed, 0xef, 0xfc,
+	0x6b, 0x41, 0xab, 0x78, 0x17, 0xea, 0xb8, 0x9b, 0xe6, 0x0a, 0xdc, 0xcf, 0xd2, 0x71, 0xcb, 0xee,
+	0x0a, 0xbc, 0xd6, 0x77, 0xd4, 0xa2, 0x01, 0x9f, 0x0e, 0x67, 0x69, 0x8c, 0x19, 0x10, 0x95, 0x72,
+	0xbe, 0xeb, 0x54, 0x1e, 0x28, 0x8f, 0x23, 0xe3, 0x40, 0x25, 0x1a, 0xce, 0x3f, 0x82, 0xfa, 0x28,
+	0x0b, 0x31, 0x86, 0x5e, 0x0e, 0x02, 0xf3, 0xa2, 0xf5, 0x41, 0x41, 0xf4, 0x34, 0x10, 0xc4, 0x57,
+	0x4c, 0x71, 0x86, 0x7f, 0x4a, 0x6e, 0x7c, 0xca, 0xca, 0xa7, 0x99, 0xa3, 0xda, 0x0d, 0xd7, 0x31,
+	0xe5, 0xab, 0xa7, 0x87, 0xa6, 0x17, 0x68, 0xe8, 0x94, 0x1e, 0x20, 0x44, 0x02, 0xed, 0x40, 0x2c,
+	0xd1, 0xf7, 0x83, 0xe9, 0x18, 0x71, 0xe4, 0x6d, 0xb0, 0x93, 0x74, 0x94, 0x2a, 0xa3, 0xe6, 0x57,
+	0x8d, 0xf4, 0xfc, 0x52, 0x51, 0xbc, 0xb3, 0x57, 0x79, 0xb7, 0x03, 0x76, 0x94, 0x4e, 0x65, 0x18,
+	0xe1, 0x45, 0xef, 0xa8, 0x8c, 0x96, 0x3a, 0x7b, 0x80, 0xbf, 0xee, 0x54, 0xa8, 0xb5, 0xf4, 0x71,
+	0xaf, 0xa2, 0x6a, 0x98, 0xa8, 0xff, 0x7c, 0xca, 0xa6, 0xd9, 0xe3, 0x68, 0xc4, 0x24, 0xf1, 0xc3,
+	0x8b, 0x2b, 0x1d, 0xd8, 0xd0, 0x49, 0x90, 0x4e, 0x26, 0xbc, 0x1b, 0x48, 0x54, 0x5c, 0xc1, 0x97,
+	0x05, 0xc9, 0x54, 0xb3, 0x48, 0x2f, 0xe5, 0x8b, 0x30, 0xe3, 0x34, 0xf4, 0x96, 0xae, 0x39, 0x87,
+	0x70, 0xec, 0x1f, 0x82, 0xbb, 0x74, 0xb8, 0xe6, 0x99, 0xc0, 0x9f, 0x94, 0xd7, 0xd6, 0x67, 0x38,
+	0xc7, 0xbf, 0xd5, 0x70, 0xe7, 0xf7, 0x12, 0x34, 0x56, 0xff, 0x50, 0x6f, 0xfc, 0xb2, 0x58, 0x7b,
+	0x08, 0x58, 0x6f, 0xf2, 0x10, 0x28, 0x1e, 0xc8, 0xf2, 0xfa, 0x81, 0x5c, 0xb9, 0x07, 0xd0, 0x5c,
+	0xd1, 0x66, 0x83, 0x74, 0xe5, 0x81, 0xfb, 0xc7, 0x5f, 0xbb, 0xa5, 0x3f, 0xf1, 0x7b, 0x85, 0xdf,
+	0x2f, 0x7f, 0xef, 0xde, 0xf9, 0xbe, 0xaa, 0x5e, 0xad, 0x9f, 0xfe, 0x07, 0x1a, 0xe0, 0x09, 0xef,
+	0xc4, 0x0a, 0x00, 0x00,
 }
@@ -1,24 +1,281 @@
 package model
 
 import (
+	"bytes"
 	"encoding/binary"
 	"errors"
 	"math"
 	"reflect"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/gogo/protobuf/proto"
+	protov2 "google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
 )
 
-var ErrWrongValue = errors.New("wrong value")
+var (
+	ErrWrongValue      = errors.New("wrong value")
+	ErrUnsupportedType = errors.New("unsupported type")
+	ErrUnknownType     = errors.New("unknown type")
+)
 
 type UserDataCodec interface {
 	EncodeUserData(interface{}) (*UserData, error)
 	DecodeUserData(*UserData) (interface{}, error)
 }
 
-type defaultCodec struct{}
+// Maps and lists are encoded as the number of elements followed by the
+// elements, each one a UserData encoded as its type, name and data, the name
+// and data prefixed by their length. The map entries are ordered by their
+// encoded key, so a map has a single encoding. Their Name holds their Go type,
+// built from the supported types only: named types are replaced by their
+// underlying type, arrays by slices and interfaces by interface {}. Nil maps
+// and slices are stored as NIL.
+
+// maxDepth bounds the nesting of maps and lists.
+const maxDepth = 32
+
+var (
+	timeType      = reflect.TypeOf(time.Time{})
+	durationType  = reflect.TypeOf(time.Duration(0))
+	interfaceType = reflect.TypeOf((*interface{})(nil)).Elem()
+
+	basicTypes = map[string]reflect.Type{
+		"bool":          reflect.TypeOf(false),
+		"int":           reflect.TypeOf(int(0)),
+		"int8":          reflect.TypeOf(int8(0)),
+		"int16":         reflect.TypeOf(int16(0)),
+		"int32":         reflect.TypeOf(int32(0)),
+		"int64":         reflect.TypeOf(int64(0)),
+		"uint":          reflect.TypeOf(uint(0)),
+		"uint8":         reflect.TypeOf(uint8(0)),
+		"uint16":        reflect.TypeOf(uint16(0)),
+		"uint32":        reflect.TypeOf(uint32(0)),
+		"uint64":        reflect.TypeOf(uint64(0)),
+		"float32":       reflect.TypeOf(float32(0)),
+		"float64":       reflect.TypeOf(float64(0)),
+		"string":        reflect.TypeOf(""),
+		"time.Time":     timeType,
+		"time.Duration": durationType,
+		"interface {}":  interfaceType,
+	}
+)
+
+// typeName returns the name a map or list of type t is stored with.
+func typeName(t reflect.Type, depth int) (string, error) {
+	if depth > maxDepth {
+		return "", ErrUnsupportedType
+	}
+	switch {
+	case t == timeType:
+		return "time.Time", nil
+	case t == durationType:
+		return "time.Duration", nil
+	}
+	switch t.Kind() {
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64, reflect.String:
+		return t.Kind().String(), nil
+	case reflect.Interface:
+		return "interface {}", nil
+	case reflect.Slice, reflect.Array:
+		elem, err := typeName(t.Elem(), depth+1)
+		return "[]" + elem, err
+	case reflect.Map:
+		key, err := typeName(t.Key(), depth+1)
+		if err != nil {
+			return "", err
+		}
+		elem, err := typeName(t.Elem(), depth+1)
+		return "map[" + key + "]" + elem, err
+	}
+	return "", ErrUnsupportedType
+}
+
+// parseType returns the type named by typeName.
+func parseType(name string, depth int) (reflect.Type, error) {
+	if depth > maxDepth {
+		return nil, ErrWrongValue
+	}
+	if t, ok := basicTypes[name]; ok {
+		return t, nil
+	}
+	if strings.HasPrefix(name, "[]") {
+		elem, err := parseType(name[2:], depth+1)
+		if err != nil {
+			return nil, err
+		}
+		return reflect.SliceOf(elem), nil
+	}
+	if strings.HasPrefix(name, "map[") {
+		// Find the bracket closing the key.
+		open := 1
+		for i := 4; i < len(name); i++ {
+			switch name[i] {
+			case '[':
+				open++
+			case ']':
+				open--
+			}
+			if open > 0 {
+				continue
+			}
+			key, err := parseType(name[4:i], depth+1)
+			if err != nil {
+				return nil, err
+			}
+			elem, err := parseType(name[i+1:], depth+1)
+			if err != nil {
+				return nil, err
+			}
+			if !key.Comparable() {
+				return nil, ErrWrongValue
+			}
+			return reflect.MapOf(key, elem), nil
+		}
+	}
+	return nil, ErrUnknownType
+}
+
+func appendUvarint(b []byte, n uint64) []byte {
+	var buf [binary.MaxVarintLen64]byte
+	return append(b, buf[:binary.PutUvarint(buf[:], n)]...)
+}
+
+func appendUserData(b []byte, userData *UserData) []byte {
+	b = appendUvarint(b, uint64(userData.Type))
+	b = appendUvarint(b, uint64(len(userData.Name)))
+	b = append(b, userData.Name...)
+	b = appendUvarint(b, uint64(len(userData.Data)))
+	return append(b, userData.Data...)
+}
+
+func readUvarint(b []byte) (uint64, []byte, error) {
+	n, size := binary.Uvarint(b)
+	if size <= 0 {
+		return 0, nil, ErrWrongValue
+	}
+	return n, b[size:], nil
+}
+
+func readBytes(b []byte) ([]byte, []byte, error) {
+	n, b, err := readUvarint(b)
+	if err != nil {
+		return nil, nil, err
+	}
+	if n > uint64(len(b)) {
+		return nil, nil, ErrWrongValue
+	}
+	return b[:n], b[n:], nil
+}
+
+func readUserData(b []byte) (*UserData, []byte, error) {
+	t, b, err := readUvarint(b)
+	if err != nil {
+		return nil, nil, err
+	}
+	if t > math.MaxInt32 {
+		return nil, nil, ErrUnknownType
+	}
+	name, b, err := readBytes(b)
+	if err != nil {
+		return nil, nil, err
+	}
+	data, b, err := readBytes(b)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &UserData{Type: UserData_Type(t), Name: string(name), Data: data}, b, nil
+}
+
+// readCount reads the number of elements of a map or list, each taking at
+// least size encoded UserData.
+func readCount(b []byte, size int) (int, []byte, error) {
+	n, b, err := readUvarint(b)
+	if err != nil {
+		return 0, nil, err
+	}
+	// An encoded UserData takes 3 bytes at least.
+	if n > uint64(len(b)/(3*size)) {
+		return 0, nil, ErrWrongValue
+	}
+	return int(n), b, nil
+}
+
+// assign sets dst, of a type returned by parseType, to a decoded element.
+func assign(dst reflect.Value, v interface{}) error {
+	if v == nil {
+		return nil
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Type().AssignableTo(dst.Type()) {
+		dst.Set(rv)
+		return nil
+	}
+	switch dst.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if rv.Kind() == reflect.Int64 && !dst.OverflowInt(rv.Int()) {
+			dst.SetInt(rv.Int())
+			return nil
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if rv.Kind() == reflect.Uint64 && !dst.OverflowUint(rv.Uint()) {
+			dst.SetUint(rv.Uint())
+			return nil
+		}
+	case reflect.Float32:
+		if rv.Kind() == reflect.Float64 {
+			dst.SetFloat(rv.Float())
+			return nil
+		}
+	}
+	return ErrWrongValue
+}
+
+// Messages of the google.golang.org/protobuf API are stored as PROTO, their
+// Name the type URL a google.protobuf.Any would hold. Gogo message names have
+// no slash, so the two are told apart by the Name.
+const typeURLPrefix = "type.googleapis.com/"
+
+type defaultCodec struct {
+	// types resolves the type URLs of APIv2 messages. Nil means
+	// protoregistry.GlobalTypes.
+	types protoregistry.MessageTypeResolver
+}
+
+// NewUserDataCodec returns a codec like DefaultUserDataCodec that resolves
+// the google.golang.org/protobuf messages it decodes with types instead of
+// protoregistry.GlobalTypes.
+func NewUserDataCodec(types protoregistry.MessageTypeResolver) UserDataCodec {
+	return defaultCodec{types: types}
+}
+
+func (c defaultCodec) messageTypes() protoregistry.MessageTypeResolver {
+	if c.types == nil {
+		return protoregistry.GlobalTypes
+	}
+	return c.types
+}
+
+func (c defaultCodec) decodeMessage(userData *UserData) (interface{}, error) {
+	msgType, err := c.messageTypes().FindMessageByURL(userData.Name)
+	if err == protoregistry.NotFound {
+		return nil, ErrUnknownType
+	} else if err != nil {
+		return nil, err
+	}
+	msg := msgType.New().Interface()
+	if err := protov2.Unmarshal(userData.Data, msg); err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
 
-func (c defaultCodec) encodeUsingReflect(rv reflect.Value) (data []byte, dataType UserData_Type) {
+func (c defaultCodec) encodeUsingReflect(rv reflect.Value, depth int) (data []byte, dataType UserData_Type, name string, err error) {
 	switch rv.Kind() {
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
 		data = make([]byte, binary.MaxVarintLen64)
@@ -42,18 +299,74 @@ func (c defaultCodec) encodeUsingReflect(rv reflect.Value) (data []byte, dataTyp
 		data = make([]byte, binary.MaxVarintLen64)
 		n := binary.PutUvarint(data, f)
 		data, dataType = data[:n], UserData_FLOAT
+	case reflect.Slice, reflect.Array:
+		if rv.Kind() == reflect.Slice && rv.IsNil() {
+			dataType = UserData_NIL
+			return
+		}
+		name, err = typeName(rv.Type(), 0)
+		if err != nil {
+			return
+		}
+		data = appendUvarint(nil, uint64(rv.Len()))
+		for i := 0; i < rv.Len(); i++ {
+			var elem *UserData
+			elem, err = c.encode(rv.Index(i).Interface(), depth+1)
+			if err != nil {
+				return
+			}
+			data = appendUserData(data, elem)
+		}
+		dataType = UserData_LIST
+	case reflect.Map:
+		if rv.IsNil() {
+			dataType = UserData_NIL
+			return
+		}
+		name, err = typeName(rv.Type(), 0)
+		if err != nil {
+			return
+		}
+		entries := make([][2][]byte, 0, rv.Len())
+		for _, k := range rv.MapKeys() {
+			var key, elem *UserData
+			key, err = c.encode(k.Interface(), depth+1)
+			if err != nil {
+				return
+			}
+			elem, err = c.encode(rv.MapIndex(k).Interface(), depth+1)
+			if err != nil {
+				return
+			}
+			entries = append(entries, [2][]byte{appendUserData(nil, key), appendUserData(nil, elem)})
+		}
+		sort.Slice(entries, func(i, j int) bool {
+			return bytes.Compare(entries[i][0], entries[j][0]) < 0
+		})
+		data = appendUvarint(nil, uint64(len(entries)))
+		for _, entry := range entries {
+			data = append(append(data, entry[0]...), entry[1]...)
+		}
+		dataType = UserData_MAP
 	default:
-		dataType = UserData_NIL
+		err = ErrUnsupportedType
 	}
 	return
 }
 
 func (c defaultCodec) EncodeUserData(v interface{}) (*UserData, error) {
+	return c.encode(v, 0)
+}
+
+func (c defaultCodec) encode(v interface{}, depth int) (*UserData, error) {
 	if v == nil {
 		return &UserData{
 			Type: UserData_NIL,
 		}, nil
 	}
+	if depth > maxDepth {
+		return nil, ErrUnsupportedType
+	}
 
 	var (
 		data     []byte
@@ -63,6 +376,13 @@ func (c defaultCodec) EncodeUserData(v interface{}) (*UserData, error) {
 	)
 
 	switch v := v.(type) {
+	// APIv2 messages implement proto.Message too, so they come first.
+	case protoreflect.ProtoMessage:
+		data, err = protov2.MarshalOptions{Deterministic: true}.Marshal(v)
+		if err == nil {
+			dataType = UserData_PROTO
+			name = typeURLPrefix + string(v.ProtoReflect().Descriptor().FullName())
+		}
 	case proto.Message:
 		data, err = proto.Marshal(v)
 		if err == nil {
@@ -73,8 +393,15 @@ func (c defaultCodec) EncodeUserData(v interface{}) (*UserData, error) {
 		data, dataType = v, UserData_BYTES
 	case string:
 		data, dataType = []byte(v), UserData_STRING
+	case time.Time:
+		data, err = v.MarshalBinary()
+		dataType = UserData_TIME
+	case time.Duration:
+		data = make([]byte, binary.MaxVarintLen64)
+		n := binary.PutVarint(data, int64(v))
+		data, dataType = data[:n], UserData_DURATION
 	default:
-		data, dataType = c.encodeUsingReflect(reflect.ValueOf(v))
+		data, dataType, name, err = c.encodeUsingReflect(reflect.ValueOf(v), depth)
 	}
 
 	if err != nil {
@@ -89,6 +416,10 @@ func (c defaultCodec) EncodeUserData(v interface{}) (*UserData, error) {
 }
 
 func (c defaultCodec) DecodeUserData(userData *UserData) (interface{}, error) {
+	return c.decode(userData, 0)
+}
+
+func (c defaultCodec) decode(userData *UserData, depth int) (interface{}, error) {
 	var (
 		v   interface{}
 		err error
@@ -97,8 +428,14 @@ func (c defaultCodec) DecodeUserData(userData *UserData) (interface{}, error) {
 	switch userData.Type {
 	case UserData_NIL:
 	case UserData_PROTO:
+		if strings.Contains(userData.Name, "/") {
+			v, err = c.decodeMessage(userData)
+			break
+		}
 		msgType := proto.MessageType(userData.Name)
-		if msgType != nil {
+		if msgType == nil {
+			err = ErrUnknownType
+		} else {
 			v = reflect.New(msgType.Elem()).Interface()
 			err = proto.Unmarshal(userData.Data, v.(proto.Message))
 		}
@@ -109,27 +446,50 @@ func (c defaultCodec) DecodeUserData(userData *UserData) (interface{}, error) {
 	case UserData_INT:
 		var n int
 		v, n = binary.Varint(userData.Data)
-		if n < 0 {
+		if n <= 0 {
 			err = ErrWrongValue
 		}
 	case UserData_UINT:
 		var n int
 		v, n = binary.Uvarint(userData.Data)
-		if n < 0 {
+		if n <= 0 {
 			err = ErrWrongValue
 		}
 	case UserData_BOOL:
 		if len(userData.Data) == 0 {
 			err = ErrWrongValue
+		} else {
+			v = userData.Data[0] != 0
 		}
-		v = userData.Data[0] != 0
 	case UserData_FLOAT:
 		f, n := binary.Uvarint(userData.Data)
-		if n < 0 {
+		if n <= 0 {
 			err = ErrWrongValue
 		} else {
 			v = math.Float64frombits(f)
 		}
+	case UserData_JSON:
+		v, err = decodeJSON(userData.Data)
+	case UserData_MAP:
+		v, err = c.decodeMap(userData, depth)
+	case UserData_LIST:
+		v, err = c.decodeList(userData, depth)
+	case UserData_TIME:
+		t := time.Time{}
+		if t.UnmarshalBinary(userData.Data) != nil {
+			err = ErrWrongValue
+		} else {
+			v = t
+		}
+	case UserData_DURATION:
+		d, n := binary.Varint(userData.Data)
+		if n <= 0 {
+			err = ErrWrongValue
+		} else {
+			v = time.Duration(d)
+		}
+	default:
+		err = ErrUnknownType
 	}
 
 	if err != nil {
@@ -138,4 +498,85 @@ func (c defaultCodec) DecodeUserData(userData *UserData) (interface{}, error) {
 	return v, nil
 }
 
+func (c defaultCodec) decodeList(userData *UserData, depth int) (interface{}, error) {
+	if depth >= maxDepth {
+		return nil, ErrWrongValue
+	}
+	t, err := parseType(userData.Name, 0)
+	if err != nil {
+		return nil, err
+	}
+	if t.Kind() != reflect.Slice {
+		return nil, ErrWrongValue
+	}
+	n, b, err := readCount(userData.Data, 1)
+	if err != nil {
+		return nil, err
+	}
+	list := reflect.MakeSlice(t, n, n)
+	for i := 0; i < n; i++ {
+		var elem *UserData
+		elem, b, err = readUserData(b)
+		if err != nil {
+			return nil, err
+		}
+		v, err := c.decode(elem, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		if err := assign(list.Index(i), v); err != nil {
+			return nil, err
+		}
+	}
+	if len(b) > 0 {
+		return nil, ErrWrongValue
+	}
+	return list.Interface(), nil
+}
+
+func (c defaultCodec) decodeMap(userData *UserData, depth int) (interface{}, error) {
+	if depth >= maxDepth {
+		return nil, ErrWrongValue
+	}
+	t, err := parseType(userData.Name, 0)
+	if err != nil {
+		return nil, err
+	}
+	if t.Kind() != reflect.Map {
+		return nil, ErrWrongValue
+	}
+	n, b, err := readCount(userData.Data, 2)
+	if err != nil {
+		return nil, err
+	}
+	m := reflect.MakeMapWithSize(t, n)
+	for i := 0; i < n; i++ {
+		var entry [2]reflect.Value
+		for j, elemType := range []reflect.Type{t.Key(), t.Elem()} {
+			var elem *UserData
+			elem, b, err = readUserData(b)
+			if err != nil {
+				return nil, err
+			}
+			v, err := c.decode(elem, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			entry[j] = reflect.New(elemType).Elem()
+			if err := assign(entry[j], v); err != nil {
+				return nil, err
+			}
+		}
+		// Interface keys must hold a comparable value.
+		if entry[0].Kind() == reflect.Interface && !entry[0].IsNil() && !entry[0].Elem().Type().Comparable() {
+			return nil, ErrWrongValue
+		}
+		m.SetMapIndex(entry[0], entry[1])
+	}
+	if len(b) > 0 {
+		return nil, ErrWrongValue
+	}
+	return m.Interface(), nil
+}
+
 var DefaultUserDataCodec UserDataCodec = defaultCodec{}
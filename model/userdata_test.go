@@ -0,0 +1,185 @@
+package model
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	protov2 "google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestDefaultCodec(t *testing.T) {
+	for _, v := range []interface{}{
+		nil,
+		&SecretHash{Algorithm: "test"},
+		[]byte("bytes"),
+		"string",
+		int64(-1),
+		uint64(1),
+		true,
+		1.5,
+		time.Date(2009, time.November, 10, 23, 0, 0, 0, time.UTC),
+		90 * time.Second,
+		map[string]string{"iss": "issuer", "sub": "alice"},
+		[]string{"admin", "user"},
+		[]int{1, -2},
+		[]float32{0.5},
+		map[string]interface{}{
+			"roles":   []string{"admin"},
+			"login":   time.Date(2009, time.November, 10, 23, 0, 0, 0, time.UTC),
+			"claims":  map[string]interface{}{"age": int64(42), "verified": true},
+			"session": nil,
+			"hash":    &SecretHash{Algorithm: "test"},
+		},
+		map[int64][]map[string]bool{1: {{"a": true}}, 2: nil},
+		[]interface{}{"a", uint64(1), []byte("b")},
+		map[string]string{},
+	} {
+		userData, err := DefaultUserDataCodec.EncodeUserData(v)
+		require.Nil(t, err)
+		decoded, err := DefaultUserDataCodec.DecodeUserData(userData)
+		require.Nil(t, err)
+		require.Equal(t, v, decoded)
+	}
+
+	for _, v := range []interface{}{
+		struct{ foo string }{"bar"},
+		[]struct{}{{}},
+		map[string]interface{}{"nested": struct{}{}},
+		&[]string{},
+	} {
+		_, err := DefaultUserDataCodec.EncodeUserData(v)
+		require.Equal(t, ErrUnsupportedType, err)
+	}
+
+	// Named and array types decode to their unnamed slice counterpart.
+	type roles []string
+	userData, err := DefaultUserDataCodec.EncodeUserData(roles{"admin"})
+	require.Nil(t, err)
+	decoded, err := DefaultUserDataCodec.DecodeUserData(userData)
+	require.Nil(t, err)
+	require.Equal(t, []string{"admin"}, decoded)
+	userData, err = DefaultUserDataCodec.EncodeUserData([2]uint8{1, 2})
+	require.Nil(t, err)
+	decoded, err = DefaultUserDataCodec.DecodeUserData(userData)
+	require.Nil(t, err)
+	require.Equal(t, []uint8{1, 2}, decoded)
+}
+
+func TestDefaultCodecIsDeterministic(t *testing.T) {
+	m := map[string]interface{}{}
+	for _, k := range []string{"a", "b", "c", "d", "e", "f", "g", "h"} {
+		m[k] = map[string]int{k: len(k), k + k: 2}
+	}
+	first, err := DefaultUserDataCodec.EncodeUserData(m)
+	require.Nil(t, err)
+	for i := 0; i < 10; i++ {
+		userData, err := DefaultUserDataCodec.EncodeUserData(m)
+		require.Nil(t, err)
+		require.Equal(t, first, userData)
+	}
+}
+
+func TestDefaultCodecProtoV2(t *testing.T) {
+	claims, err := structpb.NewStruct(map[string]interface{}{"sub": "alice", "age": 42})
+	require.Nil(t, err)
+	for _, msg := range []protov2.Message{wrapperspb.String("alice"), claims} {
+		userData, err := DefaultUserDataCodec.EncodeUserData(msg)
+		require.Nil(t, err)
+		require.Equal(t, UserData_PROTO, userData.Type)
+		require.Equal(t, "type.googleapis.com/"+string(msg.ProtoReflect().Descriptor().FullName()), userData.Name)
+		decoded, err := DefaultUserDataCodec.DecodeUserData(userData)
+		require.Nil(t, err)
+		require.True(t, protov2.Equal(msg, decoded.(protov2.Message)))
+	}
+
+	// An injected registry resolves only the types registered with it.
+	types := new(protoregistry.Types)
+	require.Nil(t, types.RegisterMessage((&wrapperspb.StringValue{}).ProtoReflect().Type()))
+	codec := NewUserDataCodec(types)
+	userData, err := codec.EncodeUserData(wrapperspb.String("alice"))
+	require.Nil(t, err)
+	decoded, err := codec.DecodeUserData(userData)
+	require.Nil(t, err)
+	require.Equal(t, "alice", decoded.(*wrapperspb.StringValue).GetValue())
+	userData, err = codec.EncodeUserData(claims)
+	require.Nil(t, err)
+	_, err = codec.DecodeUserData(userData)
+	require.Equal(t, ErrUnknownType, err)
+
+	// Gogo messages still decode with an injected registry.
+	userData, err = codec.EncodeUserData(&SecretHash{Algorithm: "test"})
+	require.Nil(t, err)
+	decoded, err = codec.DecodeUserData(userData)
+	require.Nil(t, err)
+	require.Equal(t, &SecretHash{Algorithm: "test"}, decoded)
+}
+
+func TestDefaultCodecErrors(t *testing.T) {
+	overflow := bytes.Repeat([]byte{0xff}, 11)
+	for _, c := range []struct {
+		userData *UserData
+		err      error
+	}{
+		{&UserData{Type: UserData_PROTO, Name: "unknown"}, ErrUnknownType},
+		{&UserData{Type: UserData_PROTO, Name: "model.SecretHash", Data: []byte{0xff}}, nil},
+		{&UserData{Type: UserData_PROTO, Name: "type.googleapis.com/unknown.Message"}, ErrUnknownType},
+		{&UserData{Type: UserData_PROTO, Name: "type.googleapis.com/google.protobuf.StringValue", Data: []byte{0xff}}, nil},
+		{&UserData{Type: UserData_INT}, ErrWrongValue},
+		{&UserData{Type: UserData_INT, Data: overflow}, ErrWrongValue},
+		{&UserData{Type: UserData_UINT}, ErrWrongValue},
+		{&UserData{Type: UserData_UINT, Data: overflow}, ErrWrongValue},
+		{&UserData{Type: UserData_BOOL}, ErrWrongValue},
+		{&UserData{Type: UserData_FLOAT}, ErrWrongValue},
+		{&UserData{Type: UserData_FLOAT, Data: overflow}, ErrWrongValue},
+		{&UserData{Type: UserData_JSON, Data: []byte("{")}, nil},
+		{&UserData{Type: UserData_Type(99)}, ErrUnknownType},
+		{&UserData{Type: UserData_TIME, Data: []byte{0xff}}, ErrWrongValue},
+		{&UserData{Type: UserData_DURATION}, ErrWrongValue},
+		{&UserData{Type: UserData_LIST, Name: "[]string"}, ErrWrongValue},
+		{&UserData{Type: UserData_LIST, Name: "[]string", Data: []byte{5, 3, 0, 1, 'a'}}, ErrWrongValue},
+		{&UserData{Type: UserData_LIST, Name: "[]string", Data: []byte{1, 4, 0, 1, 1}}, ErrWrongValue},
+		{&UserData{Type: UserData_LIST, Name: "[]int8", Data: []byte{1, 4, 0, 2, 0x80, 0x02}}, ErrWrongValue},
+		{&UserData{Type: UserData_LIST, Name: "[]string", Data: []byte{0, 0}}, ErrWrongValue},
+		{&UserData{Type: UserData_LIST, Name: "map[string]string", Data: []byte{0}}, ErrWrongValue},
+		{&UserData{Type: UserData_LIST, Name: "[]chan int", Data: []byte{0}}, ErrUnknownType},
+		{&UserData{Type: UserData_MAP, Name: "map[[]string]string", Data: []byte{0}}, ErrWrongValue},
+		{&UserData{Type: UserData_MAP, Name: "map[string", Data: []byte{0}}, ErrUnknownType},
+		{&UserData{Type: UserData_MAP, Name: "map[interface {}]bool", Data: []byte{1, 2, 0, 0, 6, 0, 1, 1}}, ErrWrongValue},
+	} {
+		v, err := DefaultUserDataCodec.DecodeUserData(c.userData)
+		require.NotNil(t, err, c.userData.Type.String())
+		if c.err != nil {
+			require.Equal(t, c.err, err, c.userData.Type.String())
+		}
+		require.Nil(t, v)
+	}
+}
+
+func FuzzDecodeUserData(f *testing.F) {
+	for _, v := range []interface{}{
+		"string",
+		int64(-1),
+		time.Date(2009, time.November, 10, 23, 0, 0, 0, time.UTC),
+		time.Second,
+		map[string]interface{}{"roles": []string{"admin"}, "claims": map[string]string{"sub": "alice"}},
+	} {
+		userData, err := DefaultUserDataCodec.EncodeUserData(v)
+		require.Nil(f, err)
+		f.Add(int32(userData.Type), userData.Name, userData.Data)
+	}
+	f.Fuzz(func(t *testing.T, dataType int32, name string, data []byte) {
+		v, err := DefaultUserDataCodec.DecodeUserData(&UserData{Type: UserData_Type(dataType), Name: name, Data: data})
+		if err != nil {
+			return
+		}
+		// Whatever decodes encodes again.
+		if _, err := DefaultUserDataCodec.EncodeUserData(v); err != nil {
+			t.Fatalf("%#v decoded but does not encode: %v", v, err)
+		}
+	})
+}
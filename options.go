@@ -0,0 +1,117 @@
+package boltdb
+
+import (
+	"time"
+
+	"github.com/dcalandria/osin-boltdb/model"
+)
+
+// Option configures a Storage created with New.
+type Option func(*Storage)
+
+// WithClock sets the function used to get the current time. It defaults to
+// time.Now.
+func WithClock(now func() time.Time) Option {
+	return func(s *Storage) {
+		s.now = now
+	}
+}
+
+// EnforceExpiry makes LoadAuthorize, LoadAccess and LoadRefresh return
+// storage.ErrExpired for expired records, allowing for the given clock skew.
+func EnforceExpiry(skew time.Duration) Option {
+	return func(s *Storage) {
+		s.enforceExpiry = true
+		s.clockSkew = skew
+	}
+}
+
+// WithRefreshTokenLifetime sets how long refresh tokens are valid, counted
+// from the creation of their access data. Zero, the default, means refresh
+// tokens do not expire.
+func WithRefreshTokenLifetime(d time.Duration) Option {
+	return func(s *Storage) {
+		s.refreshLifetime = d
+	}
+}
+
+// WithAccessChainDepth sets how many levels of previous access data, the
+// AccessData of osin.AccessData, are loaded along with access data. It
+// defaults to 1; 0 loads none. LoadPrevAccess loads the others on demand.
+func WithAccessChainDepth(depth int) Option {
+	return func(s *Storage) {
+		s.chainDepth = depth
+	}
+}
+
+// WithUserDataCodec sets the codec the UserData of clients, authorize data and
+// access data is stored with. It defaults to model.DefaultUserDataCodec, as
+// set when New is called.
+func WithUserDataCodec(codec model.UserDataCodec) Option {
+	return func(s *Storage) {
+		s.codec = codec
+	}
+}
+
+// WithClientCodec sets the codec converting clients to and from their stored
+// form. By default GetClient returns an *osin.DefaultClient, or a
+// *HashedClient with secret hashing.
+func WithClientCodec(codec ClientCodec) Option {
+	return func(s *Storage) {
+		s.clientCodec = codec
+	}
+}
+
+// StrictUserData makes UserData the codec fails to encode abort CreateClient,
+// UpdateClient, SaveAuthorize and SaveAccess, and UserData it fails to decode
+// fail the loaders, with a *UserDataError. By default the UserData is dropped.
+func StrictUserData() Option {
+	return func(s *Storage) {
+		s.strictUserData = true
+	}
+}
+
+// WithUserDataErrorHook sets a function called with the *UserDataError of the
+// UserData dropped when not in strict mode.
+func WithUserDataErrorHook(hook func(error)) Option {
+	return func(s *Storage) {
+		s.userDataHook = hook
+	}
+}
+
+// WithTokenHashing stores authorize codes, access tokens and refresh tokens
+// as their HMAC-SHA256 under pepper, so the raw values are never persisted.
+// InitDB migrates the records of a database created without hashing.
+func WithTokenHashing(pepper []byte) Option {
+	return func(s *Storage) {
+		s.pepper = pepper
+	}
+}
+
+// WithSecretHashing stores the hash of client secrets instead of the secrets
+// themselves. GetClient then returns a *HashedClient, which osin verifies
+// secrets with. Clients stored without a hash are upgraded on their first
+// successful match.
+func WithSecretHashing(hasher SecretHasher) Option {
+	return func(s *Storage) {
+		s.secretHasher = hasher
+	}
+}
+
+// WithEncryption encrypts stored records with AES-256-GCM under the key
+// supplied by keys. InitDB encrypts the records of a database created without
+// encryption.
+func WithEncryption(keys KeyProvider) Option {
+	return func(s *Storage) {
+		s.keys = keys
+	}
+}
+
+// WithSubjectExtractor indexes authorize and access data by the subject
+// extract returns for their UserData, for ListGrantsBySubject and
+// RevokeAllForSubject. Subjects are indexed by their HMAC, not in plain.
+func WithSubjectExtractor(extract SubjectExtractor) Option {
+	return func(s *Storage) {
+		s.subjectExtractor = extract
+	}
+}
@@ -0,0 +1,98 @@
+package boltdb
+
+import (
+	"bytes"
+	"time"
+
+	"github.com/RangelReale/osin"
+	"github.com/boltdb/bolt"
+
+	"github.com/dcalandria/osin-boltdb/model"
+)
+
+// RefreshPolicy limits the refresh token families of a client. Refreshing
+// issues a new refresh token but does not extend the family past MaxLifetime.
+type RefreshPolicy struct {
+	// MaxLifetime is how long a family is valid, counted from the issuance
+	// of its first refresh token. Zero means no limit.
+	MaxLifetime time.Duration
+	// IdleTimeout is how long a refresh token is valid if not used. Zero
+	// means no limit.
+	IdleTimeout time.Duration
+}
+
+func (s *Storage) refreshPolicy(tx *bolt.Tx, clientID string) RefreshPolicy {
+	msg := &model.Client{}
+	if s.get(tx, clientBucket, []byte(clientID), msg) != nil || msg.RefreshPolicy == nil {
+		return RefreshPolicy{}
+	}
+	return RefreshPolicy{
+		MaxLifetime: time.Duration(msg.RefreshPolicy.MaxLifetime),
+		IdleTimeout: time.Duration(msg.RefreshPolicy.IdleTimeout),
+	}
+}
+
+// GetRefreshPolicy returns the refresh policy of the client.
+func (s *Storage) GetRefreshPolicy(clientID string) (RefreshPolicy, error) {
+	var policy RefreshPolicy
+	err := s.db.View(func(tx *bolt.Tx) error {
+		if tx.Bucket(clientBucket).Get([]byte(clientID)) == nil {
+			return osin.ErrNotFound
+		}
+		policy = s.refreshPolicy(tx, clientID)
+		return nil
+	})
+	return policy, err
+}
+
+// SetRefreshPolicy stores the refresh policy of the client, applying to its
+// existing refresh tokens too. It is enforced by LoadRefresh, with or without
+// EnforceExpiry, and by Sweep, which purges the access data of refresh tokens
+// it expired.
+func (s *Storage) SetRefreshPolicy(clientID string, policy RefreshPolicy) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		msg := &model.Client{}
+		err := s.get(tx, clientBucket, []byte(clientID), msg)
+		if err != nil {
+			return err
+		}
+
+		// The expiry index entries of the access data of the client depend
+		// on the policy.
+		var tokens []*model.AccessData
+		prefix := indexPrefix(clientID)
+		c := tx.Bucket(clientIndexBucket).Cursor()
+		for k, _ := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, _ = c.Next() {
+			kind, key := splitIndexKey(prefix, k)
+			access := &model.AccessData{}
+			if kind != accessKind || s.get(tx, accessBucket, []byte(key), access) != nil {
+				continue
+			}
+			tokens = append(tokens, access)
+		}
+		for _, access := range tokens {
+			if err := s.unindexAccess(tx, access); err != nil {
+				return err
+			}
+		}
+
+		msg.RefreshPolicy = nil
+		if policy != (RefreshPolicy{}) {
+			msg.RefreshPolicy = &model.RefreshPolicy{
+				MaxLifetime: int64(policy.MaxLifetime),
+				IdleTimeout: int64(policy.IdleTimeout),
+			}
+		}
+		err = s.put(tx, clientBucket, []byte(clientID), msg)
+		if err != nil {
+			return err
+		}
+
+		for _, access := range tokens {
+			if err := s.indexAccess(tx, access); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,73 @@
+package boltdb
+
+import (
+	"testing"
+	"time"
+
+	"github.com/RangelReale/osin"
+	"github.com/pborman/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dcalandria/osin-boltdb/storage"
+)
+
+func TestRefreshPolicy(t *testing.T) {
+	start := time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)
+	clock := &testClock{start}
+	// The policy is enforced without EnforceExpiry.
+	s := newStore(t, WithClock(clock.now))
+	client := &osin.DefaultClient{Id: "policy", Secret: "secret", RedirectUri: "http://localhost/"}
+	createClient(t, s, client)
+	policy := RefreshPolicy{MaxLifetime: 3 * time.Hour, IdleTimeout: time.Hour}
+	require.Nil(t, s.SetRefreshPolicy(client.Id, policy))
+
+	// Updating the client keeps its policy.
+	client.RedirectUri = "http://localhost/callback"
+	require.Nil(t, s.UpdateClient(client))
+	loaded, err := s.GetRefreshPolicy(client.Id)
+	require.Nil(t, err)
+	require.Equal(t, policy, loaded)
+	_, err = s.GetRefreshPolicy("unknown")
+	require.Equal(t, osin.ErrNotFound, err)
+
+	access := &osin.AccessData{Client: client, AccessToken: uuid.New(), RefreshToken: uuid.New(), ExpiresIn: 60, CreatedAt: start}
+	require.Nil(t, s.SaveAccess(access))
+
+	// Refreshing within the idle timeout keeps the family alive up to its
+	// maximum lifetime.
+	for i := 0; i < 3; i++ {
+		clock.t = clock.t.Add(50 * time.Minute)
+		access = refresh(t, s, access.RefreshToken, false)
+	}
+	clock.t = clock.t.Add(50 * time.Minute)
+	_, err = s.LoadRefresh(access.RefreshToken)
+	require.Equal(t, storage.ErrExpired, err)
+
+	idle := &osin.AccessData{Client: client, AccessToken: uuid.New(), RefreshToken: uuid.New(), ExpiresIn: 60, CreatedAt: clock.t}
+	require.Nil(t, s.SaveAccess(idle))
+	clock.t = clock.t.Add(time.Hour + time.Second)
+	_, err = s.LoadRefresh(idle.RefreshToken)
+	require.Equal(t, storage.ErrExpired, err)
+}
+
+func TestSweepHonorsRefreshPolicy(t *testing.T) {
+	start := time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)
+	clock := &testClock{start}
+	s := newStore(t, WithClock(clock.now))
+	client := &osin.DefaultClient{Id: "policy", Secret: "secret", RedirectUri: "http://localhost/"}
+	createClient(t, s, client)
+	access := &osin.AccessData{Client: client, AccessToken: uuid.New(), RefreshToken: uuid.New(), ExpiresIn: 60, CreatedAt: start}
+	require.Nil(t, s.SaveAccess(access))
+
+	clock.t = start.Add(2 * time.Hour)
+	stats, err := s.Sweep()
+	require.Nil(t, err)
+	require.Equal(t, SweepStats{}, stats)
+
+	// The policy applies to the existing refresh tokens.
+	require.Nil(t, s.SetRefreshPolicy(client.Id, RefreshPolicy{IdleTimeout: time.Hour}))
+	stats, err = s.Sweep()
+	require.Nil(t, err)
+	require.Equal(t, SweepStats{Access: 1, Refresh: 1}, stats)
+	requireNoFamilies(t, s)
+}
@@ -0,0 +1,64 @@
+package boltdb
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+
+	"github.com/RangelReale/osin"
+	"github.com/boltdb/bolt"
+
+	"github.com/dcalandria/osin-boltdb/model"
+)
+
+// registrationKey returns the key a registration access token is stored
+// under. Unlike the other tokens, which the loaders return, registration
+// access tokens are only ever stored hashed.
+func registrationKey(token string) []byte {
+	sum := sha256.Sum256([]byte(token))
+	return []byte(hex.EncodeToString(sum[:]))
+}
+
+// SetRegistrationToken sets the RFC 7592 registration access token of the
+// client, replacing its previous one. An empty token removes it. RemoveClient
+// removes it too.
+func (s *Storage) SetRegistrationToken(clientID string, token string) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		msg := &model.Client{}
+		err := s.get(tx, clientBucket, []byte(clientID), msg)
+		if err != nil {
+			return err
+		}
+		if msg.RegistrationToken != "" {
+			err = s.delete(tx, registrationBucket, []byte(msg.RegistrationToken))
+			if err != nil {
+				return err
+			}
+		}
+		msg.RegistrationToken = ""
+		if token != "" {
+			key := registrationKey(token)
+			err = s.insert(tx, registrationBucket, key, []byte(clientID))
+			if err != nil {
+				return err
+			}
+			msg.RegistrationToken = string(key)
+		}
+		return s.put(tx, clientBucket, []byte(clientID), msg)
+	})
+}
+
+// LoadRegistrationToken returns the id of the client the registration access
+// token belongs to, or osin.ErrNotFound.
+func (s *Storage) LoadRegistrationToken(token string) (string, error) {
+	if token == "" {
+		return "", osin.ErrNotFound
+	}
+	var clientID string
+	err := s.db.View(func(tx *bolt.Tx) error {
+		var value []byte
+		err := s.get(tx, registrationBucket, registrationKey(token), &value)
+		clientID = string(value)
+		return err
+	})
+	return clientID, err
+}
@@ -0,0 +1,345 @@
+// Package registration serves RFC 7591 dynamic client registration and RFC
+// 7592 client configuration management, backed by a boltdb.Storage.
+package registration
+
+import (
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/RangelReale/osin"
+
+	boltdb "github.com/dcalandria/osin-boltdb"
+	"github.com/dcalandria/osin-boltdb/internal/random"
+)
+
+// Error codes of RFC 6750 and RFC 7591.
+const (
+	errorInvalidToken   = "invalid_token"
+	errorInvalidRequest = "invalid_request"
+	errorServerError    = "server_error"
+)
+
+// maxBodySize bounds the size of registration requests.
+const maxBodySize = 1 << 20
+
+// Handler is an http.Handler serving the registration endpoint, which
+// registers clients on POST, and their client configuration endpoints, the
+// endpoint URL followed by the client id, which read, update and delete them
+// on GET, PUT and DELETE.
+//
+// Registered clients are given a generated id, a generated secret unless
+// their token_endpoint_auth_method is none, and a registration access token
+// to manage their registration with.
+type Handler struct {
+	storage  *boltdb.Storage
+	endpoint string
+
+	initialAccessToken   func(token string) bool
+	redirectUriSeparator string
+	errorHook            func(error)
+}
+
+// Option configures a Handler created with New.
+type Option func(*Handler)
+
+// RequireInitialAccessToken restricts registration to requests bearing an
+// initial access token valid is true for. Registration is open by default.
+func RequireInitialAccessToken(valid func(token string) bool) Option {
+	return func(h *Handler) {
+		h.initialAccessToken = valid
+	}
+}
+
+// WithRedirectUriSeparator sets the RedirectUriSeparator of the
+// osin.ServerConfig, which the redirect URIs of registered clients are joined
+// with. Without it, osin only accepts the first redirect URI of a client.
+func WithRedirectUriSeparator(separator string) Option {
+	return func(h *Handler) {
+		h.redirectUriSeparator = separator
+	}
+}
+
+// WithErrorHook sets a function called with the errors behind server_error
+// responses.
+func WithErrorHook(hook func(error)) Option {
+	return func(h *Handler) {
+		h.errorHook = hook
+	}
+}
+
+// New returns a Handler registering clients in s. endpoint is the absolute
+// URL the registration endpoint is served at.
+func New(s *boltdb.Storage, endpoint string, opts ...Option) *Handler {
+	h := &Handler{
+		storage:  s,
+		endpoint: strings.TrimSuffix(endpoint, "/"),
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+// clientInformation is the client information response of RFC 7591 and RFC
+// 7592, also the body of RFC 7592 update requests.
+type clientInformation struct {
+	ClientId                string `json:"client_id"`
+	ClientSecret            string `json:"client_secret,omitempty"`
+	ClientIdIssuedAt        int64  `json:"client_id_issued_at,omitempty"`
+	ClientSecretExpiresAt   *int64 `json:"client_secret_expires_at,omitempty"`
+	RegistrationAccessToken string `json:"registration_access_token,omitempty"`
+	RegistrationClientUri   string `json:"registration_client_uri,omitempty"`
+	boltdb.ClientMetadata
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+		h.register(w, r)
+	case http.MethodGet, http.MethodPut, http.MethodDelete:
+		h.manage(w, r)
+	default:
+		w.Header().Set("Allow", "POST, GET, PUT, DELETE")
+		writeError(w, http.StatusMethodNotAllowed, errorInvalidRequest, "method not allowed")
+	}
+}
+
+func (h *Handler) register(w http.ResponseWriter, r *http.Request) {
+	if h.initialAccessToken != nil {
+		token := bearerToken(r)
+		if token == "" || !h.initialAccessToken(token) {
+			writeUnauthorized(w)
+			return
+		}
+	}
+
+	var metadata boltdb.ClientMetadata
+	if !decodeBody(w, r, &metadata) {
+		return
+	}
+
+	client := &osin.DefaultClient{
+		RedirectUri: h.redirectUri(metadata.RedirectUris),
+	}
+	var err error
+	client.Id, err = random.String(16, hex.EncodeToString)
+	if err == nil && metadata.TokenEndpointAuthMethod != boltdb.AuthMethodNone {
+		client.Secret, err = random.String(32, base64.RawURLEncoding.EncodeToString)
+	}
+	if err != nil {
+		h.writeServerError(w, err)
+		return
+	}
+	registered := &boltdb.RegisteredClient{Client: client, Metadata: metadata}
+	if err := h.storage.CreateClient(registered); err != nil {
+		h.writeStorageError(w, err)
+		return
+	}
+
+	token, err := random.String(32, base64.RawURLEncoding.EncodeToString)
+	if err == nil {
+		err = h.storage.SetRegistrationToken(client.Id, token)
+	}
+	if err != nil {
+		// The client cannot be managed without its token.
+		if rerr := h.storage.RemoveClient(client.Id); rerr != nil {
+			err = fmt.Errorf("%v, then removing client %s: %v", err, client.Id, rerr)
+		}
+		h.writeServerError(w, err)
+		return
+	}
+
+	info, err := h.information(registered, token)
+	if err != nil {
+		h.writeStorageError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, info)
+}
+
+func (h *Handler) manage(w http.ResponseWriter, r *http.Request) {
+	token := bearerToken(r)
+	clientID, err := h.storage.LoadRegistrationToken(token)
+	if err != nil || clientID != path.Base(r.URL.Path) {
+		writeUnauthorized(w)
+		return
+	}
+	client, err := h.storage.GetClient(clientID)
+	if err != nil {
+		h.writeStorageError(w, err)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		info, err := h.information(client, token)
+		if err != nil {
+			h.writeStorageError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, info)
+	case http.MethodPut:
+		h.update(w, r, client, token)
+	case http.MethodDelete:
+		if err := h.storage.RemoveClient(clientID); err != nil {
+			h.writeStorageError(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// update replaces the metadata of client, as RFC 7592 section 2.2 requires:
+// the request holds all of it, along with the client id and, optionally, the
+// current client secret.
+func (h *Handler) update(w http.ResponseWriter, r *http.Request, client osin.Client, token string) {
+	var info clientInformation
+	if !decodeBody(w, r, &info) {
+		return
+	}
+	if info.ClientId != client.GetId() {
+		writeError(w, http.StatusBadRequest, errorInvalidRequest, "client_id does not match")
+		return
+	}
+	if info.ClientSecret != "" && !secretMatches(client, info.ClientSecret) {
+		writeError(w, http.StatusBadRequest, errorInvalidRequest, "client_secret does not match")
+		return
+	}
+
+	base := client
+	if registered, ok := client.(*boltdb.RegisteredClient); ok {
+		base = registered.Client
+	}
+	// The redirect URIs of other clients, as returned with a
+	// boltdb.ClientCodec, cannot be set.
+	switch base := base.(type) {
+	case *osin.DefaultClient:
+		base.RedirectUri = h.redirectUri(info.RedirectUris)
+	case *boltdb.HashedClient:
+		base.RedirectUri = h.redirectUri(info.RedirectUris)
+	default:
+		writeError(w, http.StatusInternalServerError, errorServerError, "client type not supported")
+		return
+	}
+	registered := &boltdb.RegisteredClient{Client: base, Metadata: info.ClientMetadata}
+	if err := h.storage.UpdateClient(registered); err != nil {
+		h.writeStorageError(w, err)
+		return
+	}
+	updated, err := h.information(registered, token)
+	if err != nil {
+		h.writeStorageError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, updated)
+}
+
+func (h *Handler) information(client osin.Client, token string) (*clientInformation, error) {
+	issuedAt, err := h.storage.ClientCreatedAt(client.GetId())
+	if err != nil {
+		return nil, err
+	}
+	info := &clientInformation{
+		ClientId:                client.GetId(),
+		ClientSecret:            client.GetSecret(),
+		RegistrationAccessToken: token,
+		RegistrationClientUri:   h.endpoint + "/" + url.PathEscape(client.GetId()),
+	}
+	if !issuedAt.IsZero() {
+		info.ClientIdIssuedAt = issuedAt.Unix()
+	}
+	if info.ClientSecret != "" {
+		// Secrets do not expire.
+		info.ClientSecretExpiresAt = new(int64)
+	}
+	if metadata := boltdb.MetadataOf(client); metadata != nil {
+		info.ClientMetadata = *metadata
+	}
+	return info, nil
+}
+
+func (h *Handler) redirectUri(uris []string) string {
+	if h.redirectUriSeparator == "" {
+		if len(uris) == 0 {
+			return ""
+		}
+		return uris[0]
+	}
+	return strings.Join(uris, h.redirectUriSeparator)
+}
+
+func secretMatches(client osin.Client, secret string) bool {
+	if matcher, ok := client.(osin.ClientSecretMatcher); ok {
+		return matcher.ClientSecretMatches(secret)
+	}
+	return subtle.ConstantTimeCompare([]byte(client.GetSecret()), []byte(secret)) == 1
+}
+
+func bearerToken(r *http.Request) string {
+	const prefix = "Bearer "
+	auth := r.Header.Get("Authorization")
+	if len(auth) < len(prefix) || !strings.EqualFold(auth[:len(prefix)], prefix) {
+		return ""
+	}
+	return strings.TrimSpace(auth[len(prefix):])
+}
+
+func decodeBody(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodySize)).Decode(v)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, boltdb.ErrorInvalidClientMetadata, "malformed request body")
+		return false
+	}
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Pragma", "no-cache")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, code string, description string) {
+	writeJSON(w, status, struct {
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description,omitempty"`
+	}{code, description})
+}
+
+// writeUnauthorized rejects requests without a valid initial or registration
+// access token, as RFC 6750 section 3 describes.
+func writeUnauthorized(w http.ResponseWriter) {
+	w.Header().Set("WWW-Authenticate", `Bearer error="`+errorInvalidToken+`"`)
+	writeError(w, http.StatusUnauthorized, errorInvalidToken, "")
+}
+
+func (h *Handler) writeStorageError(w http.ResponseWriter, err error) {
+	switch e := err.(type) {
+	case *boltdb.ClientMetadataError:
+		writeError(w, http.StatusBadRequest, e.Code, e.Description)
+	default:
+		if err == osin.ErrNotFound {
+			writeUnauthorized(w)
+			return
+		}
+		h.writeServerError(w, err)
+	}
+}
+
+// writeServerError reports err to the error hook, if any, and hides it from
+// the client.
+func (h *Handler) writeServerError(w http.ResponseWriter, err error) {
+	if h.errorHook != nil {
+		h.errorHook(err)
+	}
+	writeError(w, http.StatusInternalServerError, errorServerError, "")
+}
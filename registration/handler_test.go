@@ -0,0 +1,269 @@
+package registration
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/RangelReale/osin"
+	"github.com/boltdb/bolt"
+	"github.com/stretchr/testify/require"
+
+	boltdb "github.com/dcalandria/osin-boltdb"
+	"github.com/dcalandria/osin-boltdb/model"
+)
+
+func newServer(t *testing.T, opts ...Option) (*boltdb.Storage, *httptest.Server) {
+	return newStorageServer(t, nil, opts...)
+}
+
+func newStorageServer(t *testing.T, storageOpts []boltdb.Option, opts ...Option) (*boltdb.Storage, *httptest.Server) {
+	filename := filepath.Join(t.TempDir(), "registration.db")
+	db, err := bolt.Open(filename, 0600, bolt.DefaultOptions)
+	require.Nil(t, err)
+	s := boltdb.New(db, storageOpts...)
+	require.Nil(t, s.InitDB())
+
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	mux.Handle("/register/", New(s, server.URL+"/register", opts...))
+	mux.Handle("/register", New(s, server.URL+"/register", opts...))
+	t.Cleanup(func() {
+		server.Close()
+		s.Close()
+		db.Close()
+	})
+	return s, server
+}
+
+type response struct {
+	clientInformation
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+func do(t *testing.T, method string, url string, token string, body interface{}) (*http.Response, *response) {
+	var reader *bytes.Reader
+	switch body := body.(type) {
+	case nil:
+		reader = bytes.NewReader(nil)
+	case string:
+		reader = bytes.NewReader([]byte(body))
+	default:
+		data, err := json.Marshal(body)
+		require.Nil(t, err)
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequest(method, url, reader)
+	require.Nil(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	require.Nil(t, err)
+	defer resp.Body.Close()
+	r := &response{}
+	if resp.StatusCode != http.StatusNoContent {
+		require.Nil(t, json.NewDecoder(resp.Body).Decode(r))
+	}
+	return resp, r
+}
+
+func TestRegistration(t *testing.T) {
+	now := time.Unix(1500000000, 0)
+	s, server := newStorageServer(t, []boltdb.Option{boltdb.WithClock(func() time.Time { return now })}, WithRedirectUriSeparator("|"))
+	endpoint := server.URL + "/register"
+
+	metadata := boltdb.ClientMetadata{
+		RedirectUris:  []string{"https://app/cb", "https://app/cb2"},
+		GrantTypes:    []string{"authorization_code", "refresh_token"},
+		ResponseTypes: []string{"code"},
+		ClientName:    "App",
+		Scope:         "read",
+	}
+	resp, registered := do(t, http.MethodPost, endpoint, "", metadata)
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	require.Equal(t, "no-store", resp.Header.Get("Cache-Control"))
+	require.NotEmpty(t, registered.ClientId)
+	require.NotEmpty(t, registered.ClientSecret)
+	require.Equal(t, int64(0), *registered.ClientSecretExpiresAt)
+	require.Equal(t, now.Unix(), registered.ClientIdIssuedAt)
+	require.NotEmpty(t, registered.RegistrationAccessToken)
+	require.Equal(t, endpoint+"/"+registered.ClientId, registered.RegistrationClientUri)
+	require.Equal(t, metadata, registered.ClientMetadata)
+
+	client, err := s.GetClient(registered.ClientId)
+	require.Nil(t, err)
+	require.Equal(t, "https://app/cb|https://app/cb2", client.GetRedirectUri())
+	require.Equal(t, &metadata, boltdb.MetadataOf(client))
+	require.True(t, client.(osin.ClientSecretMatcher).ClientSecretMatches(registered.ClientSecret))
+
+	// Reading the client configuration.
+	uri, token := registered.RegistrationClientUri, registered.RegistrationAccessToken
+	resp, read := do(t, http.MethodGet, uri, token, nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, registered.ClientId, read.ClientId)
+	require.Equal(t, registered.ClientSecret, read.ClientSecret)
+	require.Equal(t, now.Unix(), read.ClientIdIssuedAt)
+	require.Equal(t, metadata, read.ClientMetadata)
+	for _, token := range []string{"", "wrong"} {
+		resp, read = do(t, http.MethodGet, uri, token, nil)
+		require.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+		require.Equal(t, `Bearer error="invalid_token"`, resp.Header.Get("WWW-Authenticate"))
+		require.Equal(t, "invalid_token", read.Error)
+	}
+	resp, _ = do(t, http.MethodGet, endpoint+"/other", token, nil)
+	require.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	// Updating it.
+	update := clientInformation{ClientId: registered.ClientId, ClientSecret: registered.ClientSecret}
+	update.ClientMetadata = boltdb.ClientMetadata{
+		RedirectUris: []string{"https://app/callback"},
+		ClientName:   "New App",
+	}
+	resp, updated := do(t, http.MethodPut, uri, token, update)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, now.Unix(), updated.ClientIdIssuedAt)
+	require.Equal(t, update.ClientMetadata, updated.ClientMetadata)
+	client, err = s.GetClient(registered.ClientId)
+	require.Nil(t, err)
+	require.Equal(t, "https://app/callback", client.GetRedirectUri())
+	require.Equal(t, "New App", boltdb.MetadataOf(client).ClientName)
+	require.True(t, client.(osin.ClientSecretMatcher).ClientSecretMatches(registered.ClientSecret))
+
+	for _, c := range []struct {
+		info clientInformation
+		code string
+	}{
+		{clientInformation{ClientId: "other", ClientMetadata: update.ClientMetadata}, "invalid_request"},
+		{clientInformation{ClientId: registered.ClientId, ClientSecret: "wrong", ClientMetadata: update.ClientMetadata}, "invalid_request"},
+		{clientInformation{ClientId: registered.ClientId}, boltdb.ErrorInvalidRedirectURI},
+	} {
+		resp, updated = do(t, http.MethodPut, uri, token, c.info)
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		require.Equal(t, c.code, updated.Error)
+	}
+
+	// Deleting it.
+	resp, _ = do(t, http.MethodDelete, uri, token, nil)
+	require.Equal(t, http.StatusNoContent, resp.StatusCode)
+	_, err = s.GetClient(registered.ClientId)
+	require.Equal(t, osin.ErrNotFound, err)
+	resp, _ = do(t, http.MethodGet, uri, token, nil)
+	require.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+}
+
+func TestRegistrationErrors(t *testing.T) {
+	_, server := newServer(t)
+	endpoint := server.URL + "/register"
+
+	resp, r := do(t, http.MethodPost, endpoint, "", "{")
+	require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	require.Equal(t, boltdb.ErrorInvalidClientMetadata, r.Error)
+
+	resp, r = do(t, http.MethodPost, endpoint, "", boltdb.ClientMetadata{RedirectUris: []string{"/cb"}})
+	require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	require.Equal(t, boltdb.ErrorInvalidRedirectURI, r.Error)
+
+	resp, _ = do(t, http.MethodPatch, endpoint, "", nil)
+	require.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+
+	// Public clients are not given a secret.
+	resp, r = do(t, http.MethodPost, endpoint, "", boltdb.ClientMetadata{
+		RedirectUris:            []string{"https://app/cb"},
+		TokenEndpointAuthMethod: boltdb.AuthMethodNone,
+	})
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	require.Empty(t, r.ClientSecret)
+	require.Nil(t, r.ClientSecretExpiresAt)
+}
+
+type wrappedClient struct {
+	osin.Client
+}
+
+func (c *wrappedClient) Unwrap() osin.Client {
+	return c.Client
+}
+
+type wrappingCodec struct{}
+
+func (wrappingCodec) EncodeClient(client osin.Client, msg *model.Client) error {
+	return nil
+}
+
+func (wrappingCodec) DecodeClient(msg *model.Client, base osin.Client) (osin.Client, error) {
+	return &wrappedClient{base}, nil
+}
+
+func TestUpdateUnsupportedClient(t *testing.T) {
+	s, server := newStorageServer(t, []boltdb.Option{boltdb.WithClientCodec(wrappingCodec{})})
+	metadata := boltdb.ClientMetadata{RedirectUris: []string{"https://app/cb"}}
+	resp, registered := do(t, http.MethodPost, server.URL+"/register", "", metadata)
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	update := clientInformation{ClientId: registered.ClientId}
+	update.ClientMetadata = boltdb.ClientMetadata{RedirectUris: []string{"https://app/callback"}}
+	resp, updated := do(t, http.MethodPut, registered.RegistrationClientUri, registered.RegistrationAccessToken, update)
+	require.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+	require.Equal(t, errorServerError, updated.Error)
+	client, err := s.GetClient(registered.ClientId)
+	require.Nil(t, err)
+	require.Equal(t, "https://app/cb", client.GetRedirectUri())
+}
+
+// failingKeys encrypts but fails to decrypt.
+type failingKeys struct{}
+
+func (failingKeys) ActiveKey() (string, []byte, error) {
+	return "k1", make([]byte, 32), nil
+}
+
+func (failingKeys) Key(id string) ([]byte, error) {
+	return nil, errors.New("key unavailable")
+}
+
+func TestRegistrationRollback(t *testing.T) {
+	var reported []error
+	s, server := newStorageServer(t, []boltdb.Option{boltdb.WithEncryption(failingKeys{})}, WithErrorHook(func(err error) {
+		reported = append(reported, err)
+	}))
+	resp, r := do(t, http.MethodPost, server.URL+"/register", "", boltdb.ClientMetadata{RedirectUris: []string{"https://app/cb"}})
+	require.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+	require.Equal(t, errorServerError, r.Error)
+	require.Len(t, reported, 1)
+	require.Equal(t, "key unavailable", reported[0].Error())
+
+	// The client is removed, as it cannot be managed without its token.
+	clients, _, err := s.ListClients("", 0, nil)
+	require.Nil(t, err)
+	require.Len(t, clients, 0)
+}
+
+func TestInitialAccessToken(t *testing.T) {
+	_, server := newServer(t, RequireInitialAccessToken(func(token string) bool {
+		return token == "initial"
+	}))
+	endpoint := server.URL + "/register"
+	metadata := boltdb.ClientMetadata{RedirectUris: []string{"https://app/cb"}}
+
+	for _, token := range []string{"", "wrong"} {
+		resp, r := do(t, http.MethodPost, endpoint, token, metadata)
+		require.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+		require.Equal(t, "invalid_token", r.Error)
+	}
+	resp, r := do(t, http.MethodPost, endpoint, "initial", metadata)
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	// The initial access token does not grant access to the registration.
+	resp, _ = do(t, http.MethodGet, r.RegistrationClientUri, "initial", nil)
+	require.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	resp, _ = do(t, http.MethodGet, r.RegistrationClientUri, r.RegistrationAccessToken, nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+}
@@ -0,0 +1,38 @@
+package boltdb
+
+import (
+	"testing"
+
+	"github.com/RangelReale/osin"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistrationToken(t *testing.T) {
+	s := newStore(t)
+	client := &osin.DefaultClient{Id: "registered", Secret: "secret", RedirectUri: "http://localhost/"}
+	createClient(t, s, client)
+	require.Equal(t, osin.ErrNotFound, s.SetRegistrationToken("unknown", "token"))
+
+	require.Nil(t, s.SetRegistrationToken("registered", "token"))
+	requireNotStored(t, s, "token")
+	clientID, err := s.LoadRegistrationToken("token")
+	require.Nil(t, err)
+	require.Equal(t, "registered", clientID)
+	_, err = s.LoadRegistrationToken("")
+	require.Equal(t, osin.ErrNotFound, err)
+
+	// Updating the client keeps its token, setting another replaces it.
+	require.Nil(t, s.UpdateClient(client))
+	_, err = s.LoadRegistrationToken("token")
+	require.Nil(t, err)
+	require.Nil(t, s.SetRegistrationToken("registered", "rotated"))
+	_, err = s.LoadRegistrationToken("token")
+	require.Equal(t, osin.ErrNotFound, err)
+	clientID, err = s.LoadRegistrationToken("rotated")
+	require.Nil(t, err)
+	require.Equal(t, "registered", clientID)
+
+	require.Nil(t, s.RemoveClient("registered"))
+	_, err = s.LoadRegistrationToken("rotated")
+	require.Equal(t, osin.ErrNotFound, err)
+}
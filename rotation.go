@@ -0,0 +1,149 @@
+package boltdb
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"time"
+
+	"github.com/RangelReale/osin"
+	"github.com/boltdb/bolt"
+
+	"github.com/dcalandria/osin-boltdb/internal/random"
+	"github.com/dcalandria/osin-boltdb/model"
+)
+
+// ClientSecret describes a secret set by RotateClientSecret, which alone
+// returns the secret itself.
+type ClientSecret struct {
+	Id        string
+	CreatedAt time.Time
+	// ExpiresAt is when the secret stops being accepted, or zero if it does
+	// not expire.
+	ExpiresAt time.Time
+}
+
+func secretExpired(secret *model.ClientSecret, now time.Time) bool {
+	if len(secret.ExpiresAt) == 0 {
+		return false
+	}
+	var expiresAt time.Time
+	expiresAt.UnmarshalBinary(secret.ExpiresAt)
+	return !now.Before(expiresAt)
+}
+
+// RotateClientSecret gives the client a new secret, returned along with its
+// id. Its previous secrets, including the one it was created or updated with,
+// are still accepted for gracePeriod, so the instances of the client can
+// switch to the new secret one at a time. Secrets due to expire sooner keep
+// their expiry, expired secrets are deleted.
+func (s *Storage) RotateClientSecret(clientID string, gracePeriod time.Duration) (secretID string, secret string, err error) {
+	secretID, err = random.String(8, hex.EncodeToString)
+	if err != nil {
+		return "", "", err
+	}
+	secret, err = random.String(32, base64.RawURLEncoding.EncodeToString)
+	if err != nil {
+		return "", "", err
+	}
+	err = s.db.Update(func(tx *bolt.Tx) error {
+		msg := &model.Client{}
+		err := s.get(tx, clientBucket, []byte(clientID), msg)
+		if err != nil {
+			return err
+		}
+
+		now := s.now()
+		createdAt, _ := now.MarshalBinary()
+		expiresAt, _ := now.Add(gracePeriod).MarshalBinary()
+		secrets := msg.Secrets
+		if msg.Secret != "" || msg.SecretHash != nil {
+			id, err := random.String(8, hex.EncodeToString)
+			if err != nil {
+				return err
+			}
+			secrets = append(secrets, &model.ClientSecret{Id: id, Secret: msg.Secret, SecretHash: msg.SecretHash})
+			msg.Secret, msg.SecretHash = "", nil
+		}
+		msg.Secrets = nil
+		for _, old := range secrets {
+			if !secretExpired(old, now.Add(gracePeriod)) {
+				old.ExpiresAt = expiresAt
+			}
+			if !secretExpired(old, now) {
+				msg.Secrets = append(msg.Secrets, old)
+			}
+		}
+		msg.Secrets = append(msg.Secrets, &model.ClientSecret{Id: secretID, Secret: secret, CreatedAt: createdAt})
+
+		if s.secretHasher != nil {
+			err = s.hashClientSecret(msg)
+			if err != nil {
+				return err
+			}
+		}
+		return s.put(tx, clientBucket, []byte(clientID), msg)
+	})
+	if err != nil {
+		return "", "", err
+	}
+	return secretID, secret, nil
+}
+
+// RevokeClientSecret deletes the secret of the client set by
+// RotateClientSecret, or returns osin.ErrNotFound.
+func (s *Storage) RevokeClientSecret(clientID string, secretID string) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		msg := &model.Client{}
+		err := s.get(tx, clientBucket, []byte(clientID), msg)
+		if err != nil {
+			return err
+		}
+		for i, secret := range msg.Secrets {
+			if secret.Id == secretID {
+				msg.Secrets = append(msg.Secrets[:i], msg.Secrets[i+1:]...)
+				return s.put(tx, clientBucket, []byte(clientID), msg)
+			}
+		}
+		return osin.ErrNotFound
+	})
+}
+
+// GetClientSecrets returns the unexpired secrets of the client set by
+// RotateClientSecret, the newest last.
+func (s *Storage) GetClientSecrets(clientID string) ([]ClientSecret, error) {
+	var secrets []ClientSecret
+	err := s.db.View(func(tx *bolt.Tx) error {
+		msg := &model.Client{}
+		err := s.get(tx, clientBucket, []byte(clientID), msg)
+		if err != nil {
+			return err
+		}
+		now := s.now()
+		for _, secret := range msg.Secrets {
+			if secretExpired(secret, now) {
+				continue
+			}
+			info := ClientSecret{Id: secret.Id}
+			if len(secret.CreatedAt) > 0 {
+				info.CreatedAt.UnmarshalBinary(secret.CreatedAt)
+			}
+			if len(secret.ExpiresAt) > 0 {
+				info.ExpiresAt.UnmarshalBinary(secret.ExpiresAt)
+			}
+			secrets = append(secrets, info)
+		}
+		return nil
+	})
+	return secrets, err
+}
+
+// MatchedSecretID returns the id of the secret set by RotateClientSecret the
+// client, as returned by GetClient, last matched in ClientSecretMatches. It
+// returns an empty string if the client matched the secret it was created or
+// updated with.
+func MatchedSecretID(client osin.Client) string {
+	if c, ok := unwrapClient(client).(*HashedClient); ok {
+		return c.SecretID()
+	}
+	return ""
+}
@@ -0,0 +1,139 @@
+package boltdb
+
+import (
+	"testing"
+	"time"
+
+	"github.com/RangelReale/osin"
+	"github.com/stretchr/testify/require"
+)
+
+func requireSecretMatches(t *testing.T, s *Storage, clientID string, secret string, secretID string) {
+	client, err := s.GetClient(clientID)
+	require.Nil(t, err)
+	require.True(t, client.(osin.ClientSecretMatcher).ClientSecretMatches(secret), secret)
+	require.Equal(t, secretID, MatchedSecretID(client))
+}
+
+func requireSecretRejected(t *testing.T, s *Storage, clientID string, secret string) {
+	client, err := s.GetClient(clientID)
+	require.Nil(t, err)
+	require.False(t, client.(osin.ClientSecretMatcher).ClientSecretMatches(secret), secret)
+}
+
+func TestRotateClientSecret(t *testing.T) {
+	start := time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)
+	clock := &testClock{start}
+	s := newStore(t, WithClock(clock.now))
+	createClient(t, s, &osin.DefaultClient{Id: "rotated", Secret: "secret", RedirectUri: "http://localhost/"})
+	_, _, err := s.RotateClientSecret("unknown", time.Hour)
+	require.Equal(t, osin.ErrNotFound, err)
+
+	id1, secret1, err := s.RotateClientSecret("rotated", time.Hour)
+	require.Nil(t, err)
+	require.NotEqual(t, "secret", secret1)
+	client, err := s.GetClient("rotated")
+	require.Nil(t, err)
+	require.IsType(t, &HashedClient{}, client)
+	require.Equal(t, secret1, client.GetSecret())
+	requireSecretRejected(t, s, "rotated", "wrong")
+	requireSecretMatches(t, s, "rotated", secret1, id1)
+
+	// The previous secret was given an id and expires after the grace period.
+	secrets, err := s.GetClientSecrets("rotated")
+	require.Nil(t, err)
+	require.Len(t, secrets, 2)
+	id0 := secrets[0].Id
+	require.NotEqual(t, "", id0)
+	require.Equal(t, start.Add(time.Hour), secrets[0].ExpiresAt)
+	require.Equal(t, ClientSecret{Id: id1, CreatedAt: start}, secrets[1])
+	requireSecretMatches(t, s, "rotated", "secret", id0)
+	clock.t = start.Add(time.Hour)
+	requireSecretRejected(t, s, "rotated", "secret")
+	requireSecretMatches(t, s, "rotated", secret1, id1)
+
+	// Without a grace period, the previous secrets expire at once.
+	id2, secret2, err := s.RotateClientSecret("rotated", 0)
+	require.Nil(t, err)
+	requireSecretRejected(t, s, "rotated", secret1)
+	requireSecretMatches(t, s, "rotated", secret2, id2)
+	secrets, err = s.GetClientSecrets("rotated")
+	require.Nil(t, err)
+	require.Equal(t, []ClientSecret{{Id: id2, CreatedAt: clock.t}}, secrets)
+
+	// Updating a loaded client keeps its secrets, updating it with a secret
+	// adds it.
+	client, err = s.GetClient("rotated")
+	require.Nil(t, err)
+	client.(*HashedClient).RedirectUri = "http://localhost/callback"
+	require.Nil(t, s.UpdateClient(client))
+	requireSecretMatches(t, s, "rotated", secret2, id2)
+	require.Nil(t, s.UpdateClient(&osin.DefaultClient{Id: "rotated", Secret: "updated", RedirectUri: "http://localhost/"}))
+	requireSecretMatches(t, s, "rotated", "updated", "")
+	requireSecretMatches(t, s, "rotated", secret2, id2)
+	client, err = s.GetClient("rotated")
+	require.Nil(t, err)
+	require.Equal(t, "updated", client.GetSecret())
+
+	require.Nil(t, s.RevokeClientSecret("rotated", id2))
+	requireSecretRejected(t, s, "rotated", secret2)
+	requireSecretMatches(t, s, "rotated", "updated", "")
+	require.Equal(t, osin.ErrNotFound, s.RevokeClientSecret("rotated", id2))
+	require.Equal(t, osin.ErrNotFound, s.RevokeClientSecret("unknown", id2))
+}
+
+func TestRotateClientSecretGracePeriods(t *testing.T) {
+	start := time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)
+	clock := &testClock{start}
+	s := newStore(t, WithClock(clock.now))
+	createClient(t, s, &osin.DefaultClient{Id: "rotated", Secret: "secret", RedirectUri: "http://localhost/"})
+
+	// A secret due to expire sooner keeps its expiry.
+	id1, secret1, err := s.RotateClientSecret("rotated", time.Minute)
+	require.Nil(t, err)
+	id2, secret2, err := s.RotateClientSecret("rotated", time.Hour)
+	require.Nil(t, err)
+	clock.t = start.Add(time.Minute)
+	requireSecretRejected(t, s, "rotated", "secret")
+	requireSecretMatches(t, s, "rotated", secret1, id1)
+	clock.t = start.Add(time.Hour)
+	requireSecretRejected(t, s, "rotated", secret1)
+	requireSecretMatches(t, s, "rotated", secret2, id2)
+}
+
+func TestRotateClientSecretHashing(t *testing.T) {
+	s := newStore(t, WithSecretHashing(PBKDF2Hasher(1000)))
+	createClient(t, s, &RegisteredClient{
+		Client:   &osin.DefaultClient{Id: "rotated", Secret: "secret", RedirectUri: "https://app/cb"},
+		Metadata: ClientMetadata{RedirectUris: []string{"https://app/cb"}},
+	})
+	id, secret, err := s.RotateClientSecret("rotated", time.Hour)
+	require.Nil(t, err)
+	requireNotStored(t, s, "secret", secret)
+	requireSecretMatches(t, s, "rotated", secret, id)
+	requireSecretRejected(t, s, "rotated", "wrong")
+
+	client, err := s.GetClient("rotated")
+	require.Nil(t, err)
+	require.Equal(t, "", client.GetSecret())
+	require.True(t, client.(osin.ClientSecretMatcher).ClientSecretMatches("secret"))
+	require.NotEqual(t, "", MatchedSecretID(client))
+}
+
+func TestRotateClientSecretUpgrade(t *testing.T) {
+	plain := newStore(t)
+	createClient(t, plain, &osin.DefaultClient{Id: "rotated", Secret: "secret", RedirectUri: "http://localhost/"})
+	id, secret, err := plain.RotateClientSecret("rotated", time.Hour)
+	require.Nil(t, err)
+
+	// Enabling secret hashing hashes the rotated secrets on the next update.
+	s := New(plain.db, WithSecretHashing(BcryptHasher(4)))
+	client, err := s.GetClient("rotated")
+	require.Nil(t, err)
+	require.Nil(t, s.UpdateClient(client))
+	requireNotStored(t, s, "secret", secret)
+	requireSecretMatches(t, s, "rotated", secret, id)
+	client, err = s.GetClient("rotated")
+	require.Nil(t, err)
+	require.True(t, client.(osin.ClientSecretMatcher).ClientSecretMatches("secret"))
+}
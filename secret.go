@@ -0,0 +1,271 @@
+package boltdb
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+
+	"github.com/RangelReale/osin"
+	"github.com/boltdb/bolt"
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+	"golang.org/x/crypto/pbkdf2"
+
+	"github.com/dcalandria/osin-boltdb/model"
+)
+
+// Secret hashing algorithms, as recorded in model.SecretHash.
+const (
+	AlgorithmBcrypt   = "bcrypt"
+	AlgorithmPBKDF2   = "pbkdf2-sha256"
+	AlgorithmArgon2id = "argon2id"
+)
+
+const (
+	saltSize = 16
+	hashSize = 32
+)
+
+// SecretHasher hashes client secrets.
+type SecretHasher interface {
+	HashSecret(secret string) (*model.SecretHash, error)
+}
+
+type bcryptHasher struct {
+	cost int
+}
+
+// BcryptHasher returns a SecretHasher using bcrypt with the given cost.
+func BcryptHasher(cost int) SecretHasher {
+	return bcryptHasher{cost}
+}
+
+func (h bcryptHasher) HashSecret(secret string) (*model.SecretHash, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(secret), h.cost)
+	if err != nil {
+		return nil, err
+	}
+	return &model.SecretHash{
+		Algorithm:  AlgorithmBcrypt,
+		Hash:       hash,
+		Iterations: uint32(h.cost),
+	}, nil
+}
+
+type pbkdf2Hasher struct {
+	iterations int
+}
+
+// PBKDF2Hasher returns a SecretHasher using PBKDF2 with HMAC-SHA256 and the
+// given number of iterations.
+func PBKDF2Hasher(iterations int) SecretHasher {
+	return pbkdf2Hasher{iterations}
+}
+
+func (h pbkdf2Hasher) HashSecret(secret string) (*model.SecretHash, error) {
+	salt, err := newSalt()
+	if err != nil {
+		return nil, err
+	}
+	return &model.SecretHash{
+		Algorithm:  AlgorithmPBKDF2,
+		Salt:       salt,
+		Hash:       pbkdf2.Key([]byte(secret), salt, h.iterations, hashSize, sha256.New),
+		Iterations: uint32(h.iterations),
+	}, nil
+}
+
+type argon2idHasher struct {
+	time    uint32
+	memory  uint32
+	threads uint8
+}
+
+// Argon2idHasher returns a SecretHasher using Argon2id with the given time
+// and memory (in KiB) costs and degree of parallelism.
+func Argon2idHasher(time, memory uint32, threads uint8) SecretHasher {
+	return argon2idHasher{time, memory, threads}
+}
+
+func (h argon2idHasher) HashSecret(secret string) (*model.SecretHash, error) {
+	salt, err := newSalt()
+	if err != nil {
+		return nil, err
+	}
+	return &model.SecretHash{
+		Algorithm:  AlgorithmArgon2id,
+		Salt:       salt,
+		Hash:       argon2.IDKey([]byte(secret), salt, h.time, h.memory, h.threads, hashSize),
+		Iterations: h.time,
+		Memory:     h.memory,
+		Threads:    uint32(h.threads),
+	}, nil
+}
+
+func newSalt() ([]byte, error) {
+	salt := make([]byte, saltSize)
+	_, err := rand.Read(salt)
+	return salt, err
+}
+
+// secretMatches reports whether secret matches hash, whatever the algorithm
+// and parameters hash was created with.
+func secretMatches(hash *model.SecretHash, secret string) bool {
+	var computed []byte
+	switch hash.Algorithm {
+	case AlgorithmBcrypt:
+		return bcrypt.CompareHashAndPassword(hash.Hash, []byte(secret)) == nil
+	case AlgorithmPBKDF2:
+		computed = pbkdf2.Key([]byte(secret), hash.Salt, int(hash.Iterations), len(hash.Hash), sha256.New)
+	case AlgorithmArgon2id:
+		computed = argon2.IDKey([]byte(secret), hash.Salt, hash.Iterations, hash.Memory, uint8(hash.Threads), uint32(len(hash.Hash)))
+	default:
+		return false
+	}
+	return subtle.ConstantTimeCompare(computed, hash.Hash) == 1
+}
+
+// HashedClient is the osin.Client returned by GetClient when secret hashing
+// is enabled, or the client has secrets set by RotateClientSecret. It holds
+// the hash of the client secrets, or the secrets themselves while they are
+// not hashed, and implements osin.ClientSecretMatcher.
+type HashedClient struct {
+	Id          string
+	RedirectUri string
+	UserData    interface{}
+
+	storage    *Storage
+	secret     string
+	secretHash *model.SecretHash
+	secrets    []*model.ClientSecret
+	secretID   string
+}
+
+func (c *HashedClient) GetId() string {
+	return c.Id
+}
+
+// GetSecret returns the active secret of the client: the one it was created
+// or updated with, or else its newest rotated secret. It returns an empty
+// string once secrets are hashed, use ClientSecretMatches instead.
+func (c *HashedClient) GetSecret() string {
+	if c.storage.secretHasher != nil || c.secretHash != nil {
+		return ""
+	}
+	if c.secret != "" {
+		return c.secret
+	}
+	now := c.storage.now()
+	for i := len(c.secrets) - 1; i >= 0; i-- {
+		if s := c.secrets[i]; s.SecretHash == nil && !secretExpired(s, now) {
+			return s.Secret
+		}
+	}
+	return ""
+}
+
+func (c *HashedClient) GetRedirectUri() string {
+	return c.RedirectUri
+}
+
+func (c *HashedClient) GetUserData() interface{} {
+	return c.UserData
+}
+
+// ClientSecretMatches implements osin.ClientSecretMatcher. It accepts the
+// secret of the client and any of its unexpired rotated secrets, recording
+// which one matched for SecretID. A client stored before secret hashing was
+// enabled has its secret hashed on the first match.
+func (c *HashedClient) ClientSecretMatches(secret string) bool {
+	if c.ownSecretMatches(secret) {
+		c.secretID = ""
+		return true
+	}
+	now := c.storage.now()
+	for _, s := range c.secrets {
+		if secretExpired(s, now) {
+			continue
+		}
+		if s.SecretHash != nil && secretMatches(s.SecretHash, secret) ||
+			s.SecretHash == nil && s.Secret != "" && subtle.ConstantTimeCompare([]byte(s.Secret), []byte(secret)) == 1 {
+			c.secretID = s.Id
+			return true
+		}
+	}
+	return false
+}
+
+func (c *HashedClient) ownSecretMatches(secret string) bool {
+	if c.secretHash != nil {
+		return secretMatches(c.secretHash, secret)
+	}
+	if c.secret == "" {
+		// As with an osin.DefaultClient, a public client, which has no
+		// secret at all, matches the empty secret.
+		return secret == "" && len(c.secrets) == 0
+	}
+	if subtle.ConstantTimeCompare([]byte(c.secret), []byte(secret)) != 1 {
+		return false
+	}
+	if hash, err := c.storage.upgradeSecret(c.Id, secret); err == nil && hash != nil {
+		c.secret, c.secretHash = "", hash
+	}
+	return true
+}
+
+// SecretID returns the id of the rotated secret the last successful
+// ClientSecretMatches call matched, or an empty string for the secret the
+// client was created or updated with. It is recorded in the HashedClient
+// alone, not stored: ask the client osin matched, e.g. the Client of the
+// osin.AccessRequest.
+func (c *HashedClient) SecretID() string {
+	return c.secretID
+}
+
+var _ osin.ClientSecretMatcher = (*HashedClient)(nil)
+
+// hashClientSecret replaces the plaintext secrets of msg by their hash.
+func (s *Storage) hashClientSecret(msg *model.Client) (err error) {
+	if msg.Secret != "" {
+		msg.SecretHash, err = s.secretHasher.HashSecret(msg.Secret)
+		if err != nil {
+			return err
+		}
+		msg.Secret = ""
+	}
+	for _, secret := range msg.Secrets {
+		if secret.Secret != "" {
+			secret.SecretHash, err = s.secretHasher.HashSecret(secret.Secret)
+			if err != nil {
+				return err
+			}
+			secret.Secret = ""
+		}
+	}
+	return nil
+}
+
+// upgradeSecret replaces the plaintext secret of client id by its hash,
+// provided it is still secret. It returns a nil hash otherwise.
+func (s *Storage) upgradeSecret(id string, secret string) (hash *model.SecretHash, err error) {
+	if s.secretHasher == nil {
+		return nil, nil
+	}
+	err = s.db.Update(func(tx *bolt.Tx) error {
+		msg := &model.Client{}
+		err := s.get(tx, clientBucket, []byte(id), msg)
+		if err != nil {
+			return err
+		}
+		if msg.SecretHash != nil || msg.Secret != secret {
+			return nil
+		}
+		hash, err = s.secretHasher.HashSecret(secret)
+		if err != nil {
+			return err
+		}
+		msg.Secret, msg.SecretHash = "", hash
+		return s.put(tx, clientBucket, []byte(id), msg)
+	})
+	return
+}
@@ -0,0 +1,86 @@
+package boltdb
+
+import (
+	"testing"
+	"time"
+
+	"github.com/RangelReale/osin"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dcalandria/osin-boltdb/model"
+)
+
+func TestSecretHashers(t *testing.T) {
+	for _, hasher := range []SecretHasher{
+		BcryptHasher(4),
+		PBKDF2Hasher(1000),
+		Argon2idHasher(1, 64, 1),
+	} {
+		hash, err := hasher.HashSecret("secret")
+		require.Nil(t, err)
+		require.NotContains(t, string(hash.Hash), "secret")
+		require.True(t, secretMatches(hash, "secret"), hash.Algorithm)
+		require.False(t, secretMatches(hash, "wrong"), hash.Algorithm)
+	}
+	require.False(t, secretMatches(&model.SecretHash{Algorithm: "unknown"}, ""))
+}
+
+func TestSecretHashing(t *testing.T) {
+	s := newStore(t, WithSecretHashing(PBKDF2Hasher(1000)))
+	createClient(t, s, &osin.DefaultClient{Id: "hashed", Secret: "secret", RedirectUri: "http://localhost/"})
+	requireNotStored(t, s, "secret")
+
+	client, err := s.GetClient("hashed")
+	require.Nil(t, err)
+	require.IsType(t, &HashedClient{}, client)
+	require.Equal(t, "", client.GetSecret())
+	matcher := client.(osin.ClientSecretMatcher)
+	require.True(t, matcher.ClientSecretMatches("secret"))
+	require.False(t, matcher.ClientSecretMatches("wrong"))
+
+	// Updating a loaded client keeps its secret.
+	client.(*HashedClient).RedirectUri = "http://localhost/callback"
+	require.Nil(t, s.UpdateClient(client))
+	client, err = s.GetClient("hashed")
+	require.Nil(t, err)
+	require.Equal(t, "http://localhost/callback", client.GetRedirectUri())
+	require.True(t, client.(osin.ClientSecretMatcher).ClientSecretMatches("secret"))
+}
+
+func TestSecretHashingPublicClient(t *testing.T) {
+	s := newStore(t, WithSecretHashing(PBKDF2Hasher(1000)))
+	createClient(t, s, &osin.DefaultClient{Id: "public", RedirectUri: "http://localhost/"})
+
+	// Like an osin.DefaultClient, a client without a secret matches the
+	// empty secret.
+	client, err := s.GetClient("public")
+	require.Nil(t, err)
+	require.True(t, client.(osin.ClientSecretMatcher).ClientSecretMatches(""))
+	require.False(t, client.(osin.ClientSecretMatcher).ClientSecretMatches("secret"))
+
+	// Once given a secret, it no longer does.
+	_, secret, err := s.RotateClientSecret("public", time.Hour)
+	require.Nil(t, err)
+	client, err = s.GetClient("public")
+	require.Nil(t, err)
+	require.False(t, client.(osin.ClientSecretMatcher).ClientSecretMatches(""))
+	require.True(t, client.(osin.ClientSecretMatcher).ClientSecretMatches(secret))
+}
+
+func TestSecretHashingUpgrade(t *testing.T) {
+	plain := newStore(t)
+	createClient(t, plain, &osin.DefaultClient{Id: "plain", Secret: "secret", RedirectUri: "http://localhost/"})
+
+	s := New(plain.db, WithSecretHashing(BcryptHasher(4)))
+	client, err := s.GetClient("plain")
+	require.Nil(t, err)
+	matcher := client.(osin.ClientSecretMatcher)
+	require.False(t, matcher.ClientSecretMatches("wrong"))
+	requireNotStored(t, s, "wrong")
+	require.True(t, matcher.ClientSecretMatches("secret"))
+	requireNotStored(t, s, "secret")
+
+	client, err = s.GetClient("plain")
+	require.Nil(t, err)
+	require.True(t, client.(osin.ClientSecretMatcher).ClientSecretMatches("secret"))
+}
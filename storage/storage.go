@@ -6,7 +6,12 @@ import (
 	"github.com/RangelReale/osin"
 )
 
-var ErrAlreadyExists = errors.New("already exists")
+var (
+	ErrAlreadyExists      = errors.New("already exists")
+	ErrExpired            = errors.New("expired")
+	ErrCodeReused         = errors.New("authorization code reused")
+	ErrRefreshTokenReused = errors.New("refresh token reused")
+)
 
 type Storage interface {
 	osin.Storage
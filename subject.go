@@ -0,0 +1,157 @@
+package boltdb
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+
+	"github.com/RangelReale/osin"
+	"github.com/boltdb/bolt"
+
+	"github.com/dcalandria/osin-boltdb/model"
+)
+
+// SubjectExtractor returns the end-user the UserData of authorize or access
+// data belongs to, or an empty string if none.
+type SubjectExtractor func(userData interface{}) string
+
+// Grant holds the authorize and access data of a subject for a client.
+type Grant struct {
+	Client    osin.Client
+	Authorize []*osin.AuthorizeData
+	Access    []*osin.AccessData
+}
+
+// subjectKeyName is the record of keysBucket holding the key subjects are
+// hashed with.
+var subjectKeyName = []byte("subject")
+
+// initSubjectKey creates the key subjects are hashed with, the first time.
+// The subject index of a database created before then holds plain subjects,
+// it is dropped so InitDB rebuilds it.
+//
+// The key is stored hex encoded: as protobuf records, it then never starts
+// with the zero byte of encrypted values, so InitDB encrypts it when
+// encryption is enabled on an existing database.
+func (s *Storage) initSubjectKey(tx *bolt.Tx) error {
+	if tx.Bucket(keysBucket).Get(subjectKeyName) != nil {
+		return nil
+	}
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return err
+	}
+	key := make([]byte, hex.EncodedLen(len(b)))
+	hex.Encode(key, b)
+	if err := s.put(tx, keysBucket, subjectKeyName, key); err != nil {
+		return err
+	}
+	if tx.Bucket(subjectIndexBucket) == nil {
+		return nil
+	}
+	return tx.DeleteBucket(subjectIndexBucket)
+}
+
+// subjectKey returns the key the subject is indexed under, its HMAC with a
+// key of the database, so the subject index does not reveal subjects. The
+// key is encrypted along with the records.
+func (s *Storage) subjectKey(tx *bolt.Tx, subject string) (string, error) {
+	var key []byte
+	if err := s.get(tx, keysBucket, subjectKeyName, &key); err != nil {
+		return "", err
+	}
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(subject))
+	return hex.EncodeToString(mac.Sum(nil)), nil
+}
+
+func (s *Storage) userDataSubject(userData *model.UserData) string {
+	if s.subjectExtractor == nil || userData == nil {
+		return ""
+	}
+	v, _ := s.codec.DecodeUserData(userData)
+	return s.subjectExtractor(v)
+}
+
+// ListGrantsBySubject returns the grants of the subject, one per client in
+// client id order, including expired data not swept yet. With token hashing,
+// their codes and tokens are hashed keys, as with ListAccessByClient.
+func (s *Storage) ListGrantsBySubject(subject string) ([]*Grant, error) {
+	tx, err := s.db.Begin(false)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var grants []*Grant
+	byClient := make(map[string]*Grant)
+	grant := func(client osin.Client) *Grant {
+		g, ok := byClient[client.GetId()]
+		if !ok {
+			g = &Grant{Client: client}
+			byClient[client.GetId()] = g
+			grants = append(grants, g)
+		}
+		return g
+	}
+
+	subject, err = s.subjectKey(tx, subject)
+	if err != nil {
+		return nil, err
+	}
+	prefix := indexPrefix(subject)
+	c := tx.Bucket(subjectIndexBucket).Cursor()
+	for k, _ := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, _ = c.Next() {
+		switch kind, key := splitIndexKey(prefix, k); kind {
+		case authorizeKind:
+			// Codes consumed by ExchangeAuthorizeCode are kept only to
+			// detect their reuse.
+			msg := &model.AuthorizeData{}
+			err := s.get(tx, authorizeBucket, []byte(key), msg)
+			if err == osin.ErrNotFound || err == nil && msg.Used {
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			authorize, err := s.authorizeData(tx, msg)
+			if err != nil {
+				return nil, err
+			}
+			g := grant(authorize.Client)
+			g.Authorize = append(g.Authorize, authorize)
+		case accessKind:
+			access, err := s.getAccess(tx, key)
+			if err == osin.ErrNotFound {
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			g := grant(access.Client)
+			g.Access = append(g.Access, access)
+		}
+	}
+
+	sort.Slice(grants, func(i, j int) bool {
+		return grants[i].Client.GetId() < grants[j].Client.GetId()
+	})
+	return grants, nil
+}
+
+// RevokeAllForSubject deletes all authorize codes, access tokens and refresh
+// tokens of the subject, returning how many were deleted.
+func (s *Storage) RevokeAllForSubject(subject string) (n int, err error) {
+	err = s.db.Update(func(tx *bolt.Tx) error {
+		key, err := s.subjectKey(tx, subject)
+		if err != nil {
+			return err
+		}
+		n, err = s.deleteIndexed(tx, subjectIndexBucket, key)
+		return err
+	})
+	return
+}
@@ -0,0 +1,145 @@
+package boltdb
+
+import (
+	"testing"
+	"time"
+
+	"github.com/RangelReale/osin"
+	"github.com/boltdb/bolt"
+	"github.com/pborman/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func stringSubject(userData interface{}) string {
+	subject, _ := userData.(string)
+	return subject
+}
+
+func TestSubjectIndex(t *testing.T) {
+	s := newStore(t, WithSubjectExtractor(stringSubject))
+	app1 := &osin.DefaultClient{Id: "app1", Secret: "secret", RedirectUri: "http://localhost/"}
+	app2 := &osin.DefaultClient{Id: "app2", Secret: "secret", RedirectUri: "http://localhost/"}
+	createClient(t, s, app1)
+	createClient(t, s, app2)
+
+	authorize := &osin.AuthorizeData{Client: app2, Code: uuid.New(), ExpiresIn: 60, CreatedAt: time.Now(), UserData: "alice"}
+	access1 := &osin.AccessData{Client: app1, AccessToken: uuid.New(), RefreshToken: uuid.New(), ExpiresIn: 60, CreatedAt: time.Now(), UserData: "alice"}
+	access2 := &osin.AccessData{Client: app2, AccessToken: uuid.New(), RefreshToken: uuid.New(), ExpiresIn: 60, CreatedAt: time.Now(), UserData: "alice"}
+	bob := &osin.AccessData{Client: app1, AccessToken: uuid.New(), RefreshToken: uuid.New(), ExpiresIn: 60, CreatedAt: time.Now(), UserData: "bob"}
+	anonymous := &osin.AccessData{Client: app1, AccessToken: uuid.New(), ExpiresIn: 60, CreatedAt: time.Now()}
+	require.Nil(t, s.SaveAuthorize(authorize))
+	require.Nil(t, s.SaveAccess(access1))
+	require.Nil(t, s.SaveAccess(access2))
+	require.Nil(t, s.SaveAccess(bob))
+	require.Nil(t, s.SaveAccess(anonymous))
+
+	grants, err := s.ListGrantsBySubject("alice")
+	require.Nil(t, err)
+	require.Len(t, grants, 2)
+	require.Equal(t, "app1", grants[0].Client.GetId())
+	require.Len(t, grants[0].Authorize, 0)
+	require.Len(t, grants[0].Access, 1)
+	require.Equal(t, access1.AccessToken, grants[0].Access[0].AccessToken)
+	require.Equal(t, "app2", grants[1].Client.GetId())
+	require.Len(t, grants[1].Authorize, 1)
+	require.Len(t, grants[1].Access, 1)
+
+	// Removed data is no longer listed, nor are consumed codes.
+	require.Nil(t, s.RemoveAuthorize(authorize.Code))
+	grants, err = s.ListGrantsBySubject("alice")
+	require.Nil(t, err)
+	require.Len(t, grants[1].Authorize, 0)
+	exchanged := &osin.AuthorizeData{Client: app2, Code: uuid.New(), ExpiresIn: 60, CreatedAt: time.Now(), UserData: "alice"}
+	require.Nil(t, s.SaveAuthorize(exchanged))
+	require.Nil(t, s.ExchangeAuthorizeCode(exchanged.Code, &osin.AccessData{Client: app2, AuthorizeData: exchanged, AccessToken: uuid.New(), ExpiresIn: 60, CreatedAt: time.Now(), UserData: "alice"}))
+	grants, err = s.ListGrantsBySubject("alice")
+	require.Nil(t, err)
+	require.Len(t, grants[1].Authorize, 0)
+	require.Len(t, grants[1].Access, 2)
+
+	n, err := s.RevokeAllForSubject("alice")
+	require.Nil(t, err)
+	require.Equal(t, 6, n)
+	grants, err = s.ListGrantsBySubject("alice")
+	require.Nil(t, err)
+	require.Len(t, grants, 0)
+	_, err = s.LoadRefresh(access2.RefreshToken)
+	require.Equal(t, osin.ErrNotFound, err)
+
+	_, err = s.LoadRefresh(bob.RefreshToken)
+	require.Nil(t, err)
+	grants, err = s.ListGrantsBySubject("bob")
+	require.Nil(t, err)
+	require.Len(t, grants, 1)
+}
+
+func TestSubjectIndexEncryption(t *testing.T) {
+	s := newStore(t, WithSubjectExtractor(stringSubject), WithEncryption(newKeyring(t)))
+	client := &osin.DefaultClient{Id: "app", Secret: "secret", RedirectUri: "http://localhost/"}
+	createClient(t, s, client)
+	access := &osin.AccessData{Client: client, AccessToken: uuid.New(), ExpiresIn: 60, CreatedAt: time.Now(), UserData: "alice@example.com"}
+	require.Nil(t, s.SaveAccess(access))
+	requireNotStored(t, s, "alice@example.com")
+
+	grants, err := s.ListGrantsBySubject("alice@example.com")
+	require.Nil(t, err)
+	require.Len(t, grants, 1)
+	n, err := s.RevokeAllForSubject("alice@example.com")
+	require.Nil(t, err)
+	require.Equal(t, 1, n)
+}
+
+func TestSubjectIndexMigration(t *testing.T) {
+	s := newStore(t, WithSubjectExtractor(stringSubject))
+	client := &osin.DefaultClient{Id: "app", Secret: "secret", RedirectUri: "http://localhost/"}
+	createClient(t, s, client)
+	access := &osin.AccessData{Client: client, AccessToken: uuid.New(), ExpiresIn: 60, CreatedAt: time.Now(), UserData: "alice"}
+	require.Nil(t, s.SaveAccess(access))
+
+	// A database indexing plain subjects has no subject key.
+	require.Nil(t, s.db.Update(func(tx *bolt.Tx) error {
+		if err := tx.Bucket(keysBucket).Delete(subjectKeyName); err != nil {
+			return err
+		}
+		b := tx.Bucket(subjectIndexBucket)
+		k, _ := b.Cursor().First()
+		if err := b.Delete(k); err != nil {
+			return err
+		}
+		return b.Put(indexKey("alice", accessKind, access.AccessToken), nil)
+	}))
+	require.Nil(t, s.InitDB())
+	require.Nil(t, s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(subjectIndexBucket).ForEach(func(k, v []byte) error {
+			require.NotContains(t, string(k), "alice")
+			return nil
+		})
+	}))
+	grants, err := s.ListGrantsBySubject("alice")
+	require.Nil(t, err)
+	require.Len(t, grants, 1)
+}
+
+func TestSubjectKeyEncryptionMigration(t *testing.T) {
+	plain := newStore(t, WithSubjectExtractor(stringSubject))
+	client := &osin.DefaultClient{Id: "app", Secret: "secret", RedirectUri: "http://localhost/"}
+	createClient(t, plain, client)
+	require.Nil(t, plain.SaveAccess(&osin.AccessData{Client: client, AccessToken: uuid.New(), ExpiresIn: 60, CreatedAt: time.Now(), UserData: "alice"}))
+
+	// The plain subject key can never be mistaken for an encrypted value.
+	require.Nil(t, plain.db.View(func(tx *bolt.Tx) error {
+		key := tx.Bucket(keysBucket).Get(subjectKeyName)
+		require.NotEqual(t, byte(encryptedMarker), key[0])
+		require.False(t, encrypted(key))
+		return nil
+	}))
+
+	s := New(plain.db, WithSubjectExtractor(stringSubject), WithEncryption(newKeyring(t)))
+	require.Nil(t, s.InitDB())
+	requireKeyID(t, s, "k1")
+	require.Nil(t, s.SaveAccess(&osin.AccessData{Client: client, AccessToken: uuid.New(), ExpiresIn: 60, CreatedAt: time.Now(), UserData: "alice"}))
+	grants, err := s.ListGrantsBySubject("alice")
+	require.Nil(t, err)
+	require.Len(t, grants, 1)
+	require.Len(t, grants[0].Access, 2)
+}
@@ -0,0 +1,211 @@
+package boltdb
+
+import (
+	"time"
+
+	"github.com/boltdb/bolt"
+
+	"github.com/dcalandria/osin-boltdb/model"
+)
+
+// sweepBatchSize bounds the number of records removed in a single write
+// transaction, so a sweep never holds the bolt write lock for long.
+const sweepBatchSize = 256
+
+// SweepStats reports how many records a sweep removed from each bucket.
+type SweepStats struct {
+	Authorize int
+	Access    int
+	Refresh   int
+}
+
+type sweeper struct {
+	stop chan struct{}
+	done chan struct{}
+}
+
+// sweepIndex walks the expiry index bucket in order, calling remove for every
+// record expired before now, in batches of sweepBatchSize records per write
+// transaction. remove must delete the index entry along with the record.
+func (s *Storage) sweepIndex(index []byte, now time.Time, remove func(tx *bolt.Tx, key string) error) error {
+	for {
+		done := false
+		err := s.db.Update(func(tx *bolt.Tx) error {
+			var keys [][]byte
+			c := tx.Bucket(index).Cursor()
+			for k, _ := c.First(); ; k, _ = c.Next() {
+				if k == nil {
+					done = true
+					break
+				}
+				if t, _ := splitExpiryKey(k); !t.Before(now) {
+					done = true
+					break
+				}
+				if len(keys) == sweepBatchSize {
+					break
+				}
+				keys = append(keys, append([]byte(nil), k...))
+			}
+			for _, k := range keys {
+				_, key := splitExpiryKey(k)
+				if err := remove(tx, key); err != nil {
+					return err
+				}
+				// The record may be gone already, leaving a stale entry.
+				if err := s.delete(tx, index, k); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil || done {
+			return err
+		}
+	}
+}
+
+// sweepBucket walks the bucket in order, calling remove for every record
+// orphaned returns true for, in batches of sweepBatchSize records per write
+// transaction.
+func (s *Storage) sweepBucket(bucket []byte, orphaned func(tx *bolt.Tx, key []byte) bool, remove func(tx *bolt.Tx, key string) error) error {
+	var from []byte
+	for {
+		err := s.db.Update(func(tx *bolt.Tx) error {
+			var keys [][]byte
+			c := tx.Bucket(bucket).Cursor()
+			k, _ := c.First()
+			if from != nil {
+				k, _ = c.Seek(from)
+			}
+			for from = nil; k != nil; k, _ = c.Next() {
+				if len(keys) == sweepBatchSize {
+					from = append([]byte(nil), k...)
+					break
+				}
+				if orphaned(tx, k) {
+					keys = append(keys, append([]byte(nil), k...))
+				}
+			}
+			for _, k := range keys {
+				if err := remove(tx, string(k)); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil || from == nil {
+			return err
+		}
+	}
+}
+
+// Sweep removes expired authorize codes and access tokens, along with their
+// refresh tokens, and refresh tokens whose access data was removed. Access
+// data holding a refresh token is kept until the refresh token expires too,
+// that is forever unless a refresh token lifetime is set. Records are kept
+// for the clock skew allowance after they expire, as LoadAuthorize and the
+// like still accept them.
+func (s *Storage) Sweep() (stats SweepStats, err error) {
+	now := s.now().Add(-s.clockSkew)
+
+	err = s.sweepIndex(authorizeExpiryBucket, now, func(tx *bolt.Tx, code string) error {
+		if tx.Bucket(authorizeBucket).Get([]byte(code)) == nil {
+			return nil
+		}
+		stats.Authorize++
+		return s.deleteAuthorize(tx, code)
+	})
+	if err != nil {
+		return
+	}
+
+	err = s.sweepIndex(accessExpiryBucket, now, func(tx *bolt.Tx, token string) error {
+		msg := &model.AccessData{}
+		if s.get(tx, accessBucket, []byte(token), msg) != nil {
+			return nil
+		}
+		// The entry is stale if the refresh token lifetime or the refresh
+		// policy of the client was changed.
+		if t := s.accessExpiresAt(tx, msg); t.IsZero() || !t.Before(now) {
+			if key := s.accessExpiryKey(tx, msg); key != nil {
+				return s.put(tx, accessExpiryBucket, key, nil)
+			}
+			return nil
+		}
+		var accessToken []byte
+		if msg.RefreshToken != "" && s.get(tx, refreshBucket, []byte(msg.RefreshToken), &accessToken) == nil && string(accessToken) == token {
+			stats.Refresh++
+			if err := s.deleteRefresh(tx, msg.RefreshToken); err != nil {
+				return err
+			}
+		}
+		stats.Access++
+		return s.deleteAccess(tx, token)
+	})
+	if err != nil {
+		return
+	}
+
+	err = s.sweepBucket(refreshBucket, func(tx *bolt.Tx, token []byte) bool {
+		var accessToken []byte
+		if s.get(tx, refreshBucket, token, &accessToken) != nil {
+			return false
+		}
+		return tx.Bucket(accessBucket).Get(accessToken) == nil
+	}, func(tx *bolt.Tx, token string) error {
+		stats.Refresh++
+		return s.deleteRefresh(tx, token)
+	})
+	return
+}
+
+// StartSweeper runs Sweep every interval in the background until StopSweeper
+// or Close is called. If report is not nil, it is called after every sweep.
+// An interval <= 0 disables the sweeper, stopping the running one if any.
+func (s *Storage) StartSweeper(interval time.Duration, report func(SweepStats, error)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stopSweeper()
+	if interval <= 0 {
+		return
+	}
+
+	sw := &sweeper{
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+	s.sweeper = sw
+
+	go func() {
+		defer close(sw.done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				stats, err := s.Sweep()
+				if report != nil {
+					report(stats, err)
+				}
+			case <-sw.stop:
+				return
+			}
+		}
+	}()
+}
+
+// StopSweeper stops the background sweeper, if any, and waits for it to exit.
+func (s *Storage) StopSweeper() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stopSweeper()
+}
+
+func (s *Storage) stopSweeper() {
+	if s.sweeper != nil {
+		close(s.sweeper.stop)
+		<-s.sweeper.done
+		s.sweeper = nil
+	}
+}
@@ -0,0 +1,154 @@
+package boltdb
+
+import (
+	"testing"
+	"time"
+
+	"github.com/RangelReale/osin"
+	"github.com/boltdb/bolt"
+	"github.com/pborman/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSweep(t *testing.T) {
+	s := newStore(t)
+	client := &osin.DefaultClient{Id: "sweep", Secret: "secret", RedirectUri: "http://localhost/"}
+	createClient(t, s, client)
+
+	past := time.Now().Add(-time.Hour).Round(time.Second)
+	expiredAuthorize := &osin.AuthorizeData{Client: client, Code: uuid.New(), ExpiresIn: 60, CreatedAt: past}
+	liveAuthorize := &osin.AuthorizeData{Client: client, Code: uuid.New(), ExpiresIn: 600, CreatedAt: time.Now()}
+	expiredAccess := &osin.AccessData{Client: client, AccessToken: uuid.New(), ExpiresIn: 60, CreatedAt: past}
+	refreshableAccess := &osin.AccessData{Client: client, AccessToken: uuid.New(), RefreshToken: uuid.New(), ExpiresIn: 60, CreatedAt: past}
+	removedAccess := &osin.AccessData{Client: client, AccessToken: uuid.New(), RefreshToken: uuid.New(), ExpiresIn: 60, CreatedAt: past}
+
+	require.Nil(t, s.SaveAuthorize(expiredAuthorize))
+	require.Nil(t, s.SaveAuthorize(liveAuthorize))
+	require.Nil(t, s.SaveAccess(expiredAccess))
+	require.Nil(t, s.SaveAccess(refreshableAccess))
+	require.Nil(t, s.SaveAccess(removedAccess))
+	require.Nil(t, s.RemoveAccess(removedAccess.AccessToken))
+	_, err := s.LoadRefresh(removedAccess.RefreshToken)
+	require.Equal(t, osin.ErrNotFound, err)
+
+	// Simulate a refresh token left behind by RemoveAccess in a database
+	// created before it removed them.
+	orphanedAccess := &osin.AccessData{Client: client, AccessToken: uuid.New(), RefreshToken: uuid.New(), ExpiresIn: 60, CreatedAt: time.Now()}
+	require.Nil(t, s.SaveAccess(orphanedAccess))
+	require.Nil(t, s.db.Update(func(tx *bolt.Tx) error {
+		return s.deleteAccess(tx, orphanedAccess.AccessToken)
+	}))
+
+	stats, err := s.Sweep()
+	require.Nil(t, err)
+	require.Equal(t, SweepStats{Authorize: 1, Access: 1, Refresh: 1}, stats)
+
+	_, err = s.LoadAuthorize(expiredAuthorize.Code)
+	require.Equal(t, osin.ErrNotFound, err)
+	_, err = s.LoadAuthorize(liveAuthorize.Code)
+	require.Nil(t, err)
+	_, err = s.LoadAccess(expiredAccess.AccessToken)
+	require.Equal(t, osin.ErrNotFound, err)
+	_, err = s.LoadRefresh(refreshableAccess.RefreshToken)
+	require.Nil(t, err)
+	require.Nil(t, s.db.View(func(tx *bolt.Tx) error {
+		require.Nil(t, tx.Bucket(refreshBucket).Get([]byte(orphanedAccess.RefreshToken)))
+		return nil
+	}))
+
+	// Nothing left to sweep.
+	stats, err = s.Sweep()
+	require.Nil(t, err)
+	require.Equal(t, SweepStats{}, stats)
+}
+
+func TestExpiryIndexIsRebuilt(t *testing.T) {
+	s := newStore(t)
+	client := &osin.DefaultClient{Id: "sweep", Secret: "secret", RedirectUri: "http://localhost/"}
+	createClient(t, s, client)
+
+	past := time.Now().Add(-time.Hour)
+	require.Nil(t, s.SaveAuthorize(&osin.AuthorizeData{Client: client, Code: uuid.New(), ExpiresIn: 60, CreatedAt: past}))
+	require.Nil(t, s.SaveAccess(&osin.AccessData{Client: client, AccessToken: uuid.New(), ExpiresIn: 60, CreatedAt: past}))
+
+	// Simulate a database created before the index existed.
+	require.Nil(t, s.db.Update(func(tx *bolt.Tx) error {
+		if err := tx.DeleteBucket(authorizeExpiryBucket); err != nil {
+			return err
+		}
+		return tx.DeleteBucket(accessExpiryBucket)
+	}))
+	require.Nil(t, s.InitDB())
+
+	stats, err := s.Sweep()
+	require.Nil(t, err)
+	require.Equal(t, SweepStats{Authorize: 1, Access: 1}, stats)
+}
+
+func TestExpiryIndexFollowsRefreshTokenLifetime(t *testing.T) {
+	s := newStore(t)
+	client := &osin.DefaultClient{Id: "sweep", Secret: "secret", RedirectUri: "http://localhost/"}
+	createClient(t, s, client)
+
+	past := time.Now().Add(-time.Hour)
+	access := &osin.AccessData{Client: client, AccessToken: uuid.New(), RefreshToken: uuid.New(), ExpiresIn: 60, CreatedAt: past}
+	require.Nil(t, s.SaveAccess(access))
+	stats, err := s.Sweep()
+	require.Nil(t, err)
+	require.Equal(t, SweepStats{}, stats)
+
+	// Setting a lifetime expires the refresh tokens saved without one.
+	s = New(s.db, WithRefreshTokenLifetime(time.Minute))
+	require.Nil(t, s.InitDB())
+	stats, err = s.Sweep()
+	require.Nil(t, err)
+	require.Equal(t, SweepStats{Access: 1, Refresh: 1}, stats)
+}
+
+func TestSweepInBatches(t *testing.T) {
+	s := newStore(t)
+	client := &osin.DefaultClient{Id: "sweep", Secret: "secret", RedirectUri: "http://localhost/"}
+	createClient(t, s, client)
+
+	past := time.Now().Add(-time.Hour)
+	n := 2*sweepBatchSize + 10
+	for i := 0; i < n; i++ {
+		require.Nil(t, s.SaveAuthorize(&osin.AuthorizeData{Client: client, Code: uuid.New(), ExpiresIn: 60, CreatedAt: past}))
+	}
+
+	stats, err := s.Sweep()
+	require.Nil(t, err)
+	require.Equal(t, n, stats.Authorize)
+}
+
+func TestSweeperStopsOnClose(t *testing.T) {
+	s := newStore(t)
+	swept := make(chan SweepStats, 1)
+	s.StartSweeper(time.Millisecond, func(stats SweepStats, err error) {
+		assert.Nil(t, err)
+		select {
+		case swept <- stats:
+		default:
+		}
+	})
+	<-swept
+
+	// Closing a clone, as osin does after every request, keeps it running.
+	s.Clone().Close()
+	s.mu.Lock()
+	require.NotNil(t, s.sweeper)
+	s.mu.Unlock()
+
+	s.Close()
+	require.Nil(t, s.sweeper)
+}
+
+func TestSweeperDisabled(t *testing.T) {
+	s := newStore(t)
+	s.StartSweeper(time.Hour, nil)
+	s.StartSweeper(0, nil)
+	require.Nil(t, s.sweeper)
+	s.StartSweeper(-time.Second, nil)
+	require.Nil(t, s.sweeper)
+}
@@ -0,0 +1,55 @@
+package boltdb
+
+import (
+	"github.com/dcalandria/osin-boltdb/model"
+)
+
+// UserDataError reports UserData the codec failed to encode or decode.
+type UserDataError struct {
+	// Op is "encode" or "decode".
+	Op string
+	// Record is the kind of record the UserData belongs to: "client",
+	// "authorize" or "access".
+	Record string
+	Err    error
+}
+
+func (e *UserDataError) Error() string {
+	return e.Op + " " + e.Record + " user data: " + e.Err.Error()
+}
+
+func (e *UserDataError) Unwrap() error {
+	return e.Err
+}
+
+// userDataFailed returns err in strict mode. Otherwise it reports err to the
+// hook, if any, and the UserData is dropped.
+func (s *Storage) userDataFailed(err *UserDataError) error {
+	if s.strictUserData {
+		return err
+	}
+	if s.userDataHook != nil {
+		s.userDataHook(err)
+	}
+	return nil
+}
+
+func (s *Storage) encodeUserData(record string, v interface{}) (*model.UserData, error) {
+	userData, err := s.codec.EncodeUserData(v)
+	if err != nil {
+		return nil, s.userDataFailed(&UserDataError{Op: "encode", Record: record, Err: err})
+	}
+	return userData, nil
+}
+
+func (s *Storage) decodeUserData(record string, userData *model.UserData) (interface{}, error) {
+	// Records whose UserData failed to encode have none.
+	if userData == nil {
+		return nil, nil
+	}
+	v, err := s.codec.DecodeUserData(userData)
+	if err != nil {
+		return nil, s.userDataFailed(&UserDataError{Op: "decode", Record: record, Err: err})
+	}
+	return v, nil
+}
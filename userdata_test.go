@@ -0,0 +1,90 @@
+package boltdb
+
+import (
+	"testing"
+	"time"
+
+	"github.com/RangelReale/osin"
+	"github.com/boltdb/bolt"
+	"github.com/pborman/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dcalandria/osin-boltdb/model"
+)
+
+type unsupported struct{ foo string }
+
+func requireUserDataError(t *testing.T, err error, op string, record string) {
+	require.IsType(t, &UserDataError{}, err)
+	require.Equal(t, op, err.(*UserDataError).Op)
+	require.Equal(t, record, err.(*UserDataError).Record)
+}
+
+// corruptUserData replaces the UserData of the stored access data with an
+// INT without data.
+func corruptUserData(t *testing.T, s *Storage, token string) {
+	require.Nil(t, s.db.Update(func(tx *bolt.Tx) error {
+		msg := &model.AccessData{}
+		require.Nil(t, s.get(tx, accessBucket, []byte(token), msg))
+		msg.UserData = &model.UserData{Type: model.UserData_INT}
+		return s.put(tx, accessBucket, []byte(token), msg)
+	}))
+}
+
+func TestStrictUserData(t *testing.T) {
+	s := newStore(t, StrictUserData())
+	client := &osin.DefaultClient{Id: "strict", Secret: "secret", RedirectUri: "http://localhost/", UserData: unsupported{"bar"}}
+	err := s.CreateClient(client)
+	requireUserDataError(t, err, "encode", "client")
+	require.Equal(t, model.ErrUnsupportedType, err.(*UserDataError).Err)
+	_, err = s.GetClient(client.Id)
+	require.Equal(t, osin.ErrNotFound, err)
+	client.UserData = "bar"
+	createClient(t, s, client)
+
+	authorize := &osin.AuthorizeData{Client: client, Code: uuid.New(), ExpiresIn: 60, CreatedAt: time.Now(), UserData: unsupported{"bar"}}
+	requireUserDataError(t, s.SaveAuthorize(authorize), "encode", "authorize")
+	_, err = s.LoadAuthorize(authorize.Code)
+	require.Equal(t, osin.ErrNotFound, err)
+
+	access := &osin.AccessData{Client: client, AccessToken: uuid.New(), ExpiresIn: 60, CreatedAt: time.Now(), UserData: unsupported{"bar"}}
+	requireUserDataError(t, s.SaveAccess(access), "encode", "access")
+	_, err = s.LoadAccess(access.AccessToken)
+	require.Equal(t, osin.ErrNotFound, err)
+
+	access.UserData = int64(1)
+	require.Nil(t, s.SaveAccess(access))
+	corruptUserData(t, s, access.AccessToken)
+	_, err = s.LoadAccess(access.AccessToken)
+	requireUserDataError(t, err, "decode", "access")
+	require.Equal(t, model.ErrWrongValue, err.(*UserDataError).Err)
+
+	// Previous access data fails the data refreshed from it.
+	refreshed := &osin.AccessData{Client: client, AccessData: access, AccessToken: uuid.New(), ExpiresIn: 60, CreatedAt: time.Now()}
+	require.Nil(t, s.SaveAccess(refreshed))
+	_, err = s.LoadAccess(refreshed.AccessToken)
+	requireUserDataError(t, err, "decode", "access")
+}
+
+func TestLenientUserData(t *testing.T) {
+	var reported []error
+	s := newStore(t, WithUserDataErrorHook(func(err error) {
+		reported = append(reported, err)
+	}))
+	client := &osin.DefaultClient{Id: "lenient", Secret: "secret", RedirectUri: "http://localhost/", UserData: unsupported{"bar"}}
+	createClient(t, s, client)
+	loaded, err := s.GetClient(client.Id)
+	require.Nil(t, err)
+	require.Nil(t, loaded.GetUserData())
+	require.Len(t, reported, 1)
+	requireUserDataError(t, reported[0], "encode", "client")
+
+	access := &osin.AccessData{Client: client, AccessToken: uuid.New(), ExpiresIn: 60, CreatedAt: time.Now(), UserData: int64(1)}
+	require.Nil(t, s.SaveAccess(access))
+	corruptUserData(t, s, access.AccessToken)
+	loadedAccess, err := s.LoadAccess(access.AccessToken)
+	require.Nil(t, err)
+	require.Nil(t, loadedAccess.UserData)
+	require.Len(t, reported, 2)
+	requireUserDataError(t, reported[1], "decode", "access")
+}